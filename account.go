@@ -0,0 +1,159 @@
+package askgo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrAccountNotLinked is returned when a request has no account linking access token, or when
+// the token is rejected by AccountLinker.Resolve
+var ErrAccountNotLinked = errors.New("askgo: the account is not linked")
+
+// AccessToken returns the account linking access token of the request, or "".  When the speaker
+// is recognized by voice and has linked their own account their token is used rather than the
+// one of the account holder.
+func AccessToken(input HandlerInput) string {
+	envelope := input.GetRequestEnvelope()
+	if person := envelope.Context.System.Person; person != nil && person.AccessToken != "" {
+		return person.AccessToken
+	}
+	if token := envelope.Context.System.User.AccessToken; token != "" {
+		return token
+	}
+	return envelope.Session.User.AccessToken
+}
+
+// AccountLinked is a Route.Predicate matching the requests with an access token
+func AccountLinked(input HandlerInput) bool {
+	return AccessToken(input) != ""
+}
+
+// AccountLinker requires the user to have linked their account, answering with a LinkAccount
+// card otherwise, and resolves the access token into the identity of the user in the skill.
+//
+//	linker := &askgo.AccountLinker{
+//		Speech:  "Please link your account in the Alexa app",
+//		Resolve: profiles.Resolve, // profiles is an *lwa.Client
+//	}
+//	skill.Handlers = append(skill.Handlers, &askgo.Route{Intents: []string{"OrderIntent"}, Handler: order,
+//		Middleware: []askgo.Middleware{linker.Middleware()}})
+type AccountLinker struct {
+	// Speech asks the user to link their account, it is looked up with HandlerInput.T
+	Speech string
+	// Resolve returns the identity of the user for the access token, an error matching
+	// ErrAccountNotLinked has the user link their account again
+	Resolve func(ctx context.Context, token string) (interface{}, error)
+	// CacheTTL is how long resolved identities are kept by token, defaults to 5 minutes
+	CacheTTL time.Duration
+
+	mutex sync.Mutex
+	cache map[string]cachedIdentity
+}
+
+type cachedIdentity struct {
+	identity interface{}
+	expires  time.Time
+}
+
+// LinkAccountResponse asks the user to link their account with a LinkAccount card in the Alexa app
+func (linker *AccountLinker) LinkAccountResponse(input HandlerInput) *ResponseEnvelope {
+	speech := "Please link your account in the Alexa app"
+	if linker.Speech != "" {
+		speech = input.T(linker.Speech)
+	}
+	return input.GetResponse().Speak(speech).WithLinkAccountCard().WithShouldEndSession(true)
+}
+
+// Token returns the access token of the request, or when there is none the response asking
+// the user to link their account
+func (linker *AccountLinker) Token(input HandlerInput) (string, *ResponseEnvelope) {
+	if token := AccessToken(input); token != "" {
+		return token, nil
+	}
+	return "", linker.LinkAccountResponse(input)
+}
+
+// Identity returns the identity of the user resolved from the access token, or the response
+// asking the user to link their account
+func (linker *AccountLinker) Identity(input HandlerInput) (interface{}, *ResponseEnvelope, error) {
+	token, response := linker.Token(input)
+	if response != nil {
+		return nil, response, nil
+	}
+	if linker.Resolve == nil {
+		return token, nil, nil
+	}
+
+	if identity, found := linker.cached(token); found {
+		return identity, nil, nil
+	}
+
+	identity, err := linker.Resolve(input.GetContext(), token)
+	if errors.Is(err, ErrAccountNotLinked) {
+		input.GetLogger().Info("Access token rejected", "error", err)
+		return nil, linker.LinkAccountResponse(input), nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	linker.store(token, identity)
+	return identity, nil, nil
+}
+
+func (linker *AccountLinker) cached(token string) (interface{}, bool) {
+	linker.mutex.Lock()
+	defer linker.mutex.Unlock()
+
+	entry, found := linker.cache[token]
+	if !found || time.Now().After(entry.expires) {
+		delete(linker.cache, token)
+		return nil, false
+	}
+	return entry.identity, true
+}
+
+func (linker *AccountLinker) store(token string, identity interface{}) {
+	ttl := linker.CacheTTL
+	if ttl == 0 {
+		ttl = 5 * time.Minute
+	}
+
+	linker.mutex.Lock()
+	defer linker.mutex.Unlock()
+
+	if linker.cache == nil {
+		linker.cache = make(map[string]cachedIdentity)
+	}
+	now := time.Now()
+	for key, entry := range linker.cache {
+		if now.After(entry.expires) {
+			delete(linker.cache, key)
+		}
+	}
+	linker.cache[token] = cachedIdentity{identity: identity, expires: now.Add(ttl)}
+}
+
+type identityKey struct{}
+
+// Middleware answers with the link account response unless the account is linked, otherwise
+// the identity of the user is available to the handler through LinkedIdentity
+func (linker *AccountLinker) Middleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(input HandlerInput) (*ResponseEnvelope, error) {
+			identity, response, err := linker.Identity(input)
+			if err != nil || response != nil {
+				return response, err
+			}
+			input.SetContext(context.WithValue(input.GetContext(), identityKey{}, identity))
+			return next(input)
+		}
+	}
+}
+
+// LinkedIdentity returns the identity resolved by the AccountLinker middleware, or nil
+func LinkedIdentity(input HandlerInput) interface{} {
+	return input.GetContext().Value(identityKey{})
+}
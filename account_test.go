@@ -0,0 +1,64 @@
+package askgo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/askgotest"
+	"github.com/koblas/askgo/lwa"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AccountLinker(t *testing.T) {
+	lookups := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		if r.Header.Get("Authorization") != "Bearer Atza|good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "invalid_token", "error_description": "The access token is expired"}`))
+			return
+		}
+		w.Write([]byte(`{"user_id": "amzn1.account.1", "name": "Ada"}`))
+	}))
+	defer server.Close()
+
+	profiles := &lwa.Client{ProfileURL: server.URL}
+	linker := &askgo.AccountLinker{
+		Speech:  "Link your account to order",
+		Resolve: profiles.Resolve,
+	}
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		Logger:          discardLogger,
+		Handlers: []askgo.RequestHandler{
+			&askgo.Route{Intents: []string{"OrderIntent"}, Middleware: []askgo.Middleware{linker.Middleware()},
+				Handler: func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+					profile := askgo.LinkedIdentity(input).(*lwa.Profile)
+					return input.GetResponse().Speak("Ordered for " + profile.Name), nil
+				}},
+			&askgo.Route{Intents: []string{"StatusIntent"}, Predicate: askgo.AccountLinked, Handler: say("Linked")},
+			askgo.IntentRoute(say("Not linked"), "StatusIntent"),
+		},
+	}
+	conv := askgotest.NewConversation(skill)
+
+	linkCard := askgotest.Expect{Speech: "Link your account to order", CardType: "LinkAccount", ShouldEndSession: askgotest.Bool(true)}
+	conv.Run(t, askgotest.Script{
+		{Intent: "OrderIntent", Expect: linkCard},
+		{Intent: "StatusIntent", Expect: askgotest.Expect{Speech: "Not linked"}},
+	})
+
+	conv.AccessToken = "Atza|good"
+	conv.Run(t, askgotest.Script{
+		{Intent: "StatusIntent", Expect: askgotest.Expect{Speech: "Linked"}},
+		{Intent: "OrderIntent", Expect: askgotest.Expect{Speech: "Ordered for Ada"}},
+		{Intent: "OrderIntent", Expect: askgotest.Expect{Speech: "Ordered for Ada"}},
+	})
+	require.Equal(t, 1, lookups, "the identity is cached")
+
+	// A rejected token asks the user to link the account again
+	conv.AccessToken = "Atza|expired"
+	conv.Run(t, askgotest.Script{{Intent: "OrderIntent", Expect: linkCard}})
+}
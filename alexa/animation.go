@@ -0,0 +1,80 @@
+package alexa
+
+// Black turns the lights of a gadget off
+const Black = "000000"
+
+// Animation builds a LightAnimation from solid, fade, blink and breathe sequences.
+//
+//	animation := alexa.NewAnimation().Breathe("00FF00", 1000, 3).Solid("00FF00", 2000).Build()
+type Animation struct {
+	repeat int
+	lights []string
+	steps  []AnimationStep
+}
+
+// NewAnimation starts an animation played once on every light
+func NewAnimation() *Animation {
+	return &Animation{repeat: 1, lights: []string{"1"}}
+}
+
+// Repeat plays the sequence the given number of times
+func (animation *Animation) Repeat(times int) *Animation {
+	animation.repeat = times
+	return animation
+}
+
+// Lights sets the lights of the gadget to animate (Echo Buttons have a single light, "1")
+func (animation *Animation) Lights(lights ...string) *Animation {
+	animation.lights = lights
+	return animation
+}
+
+// Step adds a single step to the sequence
+func (animation *Animation) Step(color string, durationMs int, blend bool) *Animation {
+	animation.steps = append(animation.steps, AnimationStep{DurationMs: durationMs, Color: color, Blend: blend})
+	return animation
+}
+
+// Solid shows the color for the duration
+func (animation *Animation) Solid(color string, durationMs int) *Animation {
+	return animation.Step(color, durationMs, false)
+}
+
+// Fade blends from one color to the other over the duration
+func (animation *Animation) Fade(from, to string, durationMs int) *Animation {
+	return animation.Step(from, 1, false).Step(to, durationMs, true)
+}
+
+// FadeIn blends from black to the color over the duration
+func (animation *Animation) FadeIn(color string, durationMs int) *Animation {
+	return animation.Fade(Black, color, durationMs)
+}
+
+// FadeOut blends from the color to black over the duration
+func (animation *Animation) FadeOut(color string, durationMs int) *Animation {
+	return animation.Fade(color, Black, durationMs)
+}
+
+// Blink turns the color on and off the given number of times
+func (animation *Animation) Blink(color string, onMs, offMs, times int) *Animation {
+	for idx := 0; idx < times; idx++ {
+		animation.Solid(color, onMs).Solid(Black, offMs)
+	}
+	return animation
+}
+
+// Breathe fades the color in and out the given number of times, each breath taking the duration
+func (animation *Animation) Breathe(color string, durationMs, times int) *Animation {
+	for idx := 0; idx < times; idx++ {
+		animation.Step(Black, 1, false).Step(color, durationMs/2, true).Step(Black, durationMs-durationMs/2, true)
+	}
+	return animation
+}
+
+// Build returns the animation
+func (animation *Animation) Build() LightAnimation {
+	steps := make([]AnimationStep, len(animation.steps))
+	copy(steps, animation.steps)
+
+	return LightAnimation{Repeat: animation.repeat, TargetLights: animation.lights, Sequence: steps}
+}
@@ -0,0 +1,66 @@
+package alexa
+
+// Header identifies the custom interface and the name of a custom event or directive
+type Header struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Endpoint is a gadget connected to the device, as returned by the endpoint enumeration API
+type Endpoint struct {
+	EndpointID   string                 `json:"endpointId"`
+	FriendlyName string                 `json:"friendlyName,omitempty"`
+	Capabilities []EndpointCapabilities `json:"capabilities,omitempty"`
+}
+
+// CustomInterfaceEvent is an event of a CustomInterfaceController.EventsReceived request
+type CustomInterfaceEvent struct {
+	Header   Header                 `json:"header"`
+	Endpoint Endpoint               `json:"endpoint"`
+	Payload  map[string]interface{} `json:"payload,omitempty"`
+}
+
+// EndpointCapabilities is an interface supported by an endpoint (e.g. "Custom.Robot")
+type EndpointCapabilities struct {
+	Type      string `json:"type"`
+	Interface string `json:"interface"`
+	Version   string `json:"version"`
+}
+
+// CustomInterfaceStartEventHandlerDirective has the custom events of the gadgets matching the
+// filter sent to the skill as CustomInterfaceController.EventsReceived requests until the
+// handler expires, then a CustomInterfaceController.Expired request is sent
+type CustomInterfaceStartEventHandlerDirective struct {
+	Type        string          `json:"type"`
+	Token       string          `json:"token"`
+	EventFilter *EventFilter    `json:"eventFilter,omitempty"`
+	Expiration  EventExpiration `json:"expiration"`
+}
+
+// EventFilter selects the events sent to the skill
+type EventFilter struct {
+	// FilterExpression is a JSON Logic expression (see the jsonlogic package)
+	FilterExpression interface{} `json:"filterExpression"`
+	// FilterMatchAction is "SEND_AND_TERMINATE" or "SEND"
+	FilterMatchAction string `json:"filterMatchAction"`
+}
+
+// EventExpiration is the duration of an event handler and the payload of its Expired request
+type EventExpiration struct {
+	DurationInMilliseconds int                    `json:"durationInMilliseconds"`
+	ExpirationPayload      map[string]interface{} `json:"expirationPayload,omitempty"`
+}
+
+// CustomInterfaceStopEventHandlerDirective stops the event handler with the token
+type CustomInterfaceStopEventHandlerDirective struct {
+	Type  string `json:"type"`
+	Token string `json:"token"`
+}
+
+// CustomInterfaceSendDirective sends a custom directive to a gadget
+type CustomInterfaceSendDirective struct {
+	Type     string      `json:"type"`
+	Header   Header      `json:"header"`
+	Endpoint Endpoint    `json:"endpoint"`
+	Payload  interface{} `json:"payload"`
+}
@@ -0,0 +1,152 @@
+package alexa
+
+import "encoding/json"
+
+// GameEngineStartInputHandlerDirective starts listening to the events of Echo Buttons, the
+// recognizers are matched against the button presses and the events are sent to the skill as
+// GameEngine.InputHandlerEvent requests when their recognizers are met.
+type GameEngineStartInputHandlerDirective struct {
+	Type string `json:"type"`
+	// Timeout in milliseconds, at most 90000
+	Timeout     int                        `json:"timeout"`
+	ProxyList   []string                   `json:"proxies,omitempty"`
+	Recognizers map[string]Recognizer      `json:"recognizers,omitempty"`
+	Events      map[string]GameEngineEvent `json:"events"`
+}
+
+// GameEngineStopInputHandlerDirective stops the input handler started by the request with the given ID
+type GameEngineStopInputHandlerDirective struct {
+	Type                 string `json:"type"`
+	OriginatingRequestID string `json:"originatingRequestId"`
+}
+
+// Recognizer is a condition on the button presses, one of PatternRecognizer, DeviationRecognizer
+// or ProgressRecognizer
+type Recognizer interface {
+	RecognizerType() string
+}
+
+// Pattern is a step of a PatternRecognizer, empty fields match any gadget, color or action
+type Pattern struct {
+	GadgetIDs []string `json:"gadgetIds,omitempty"`
+	Colors    []string `json:"colors,omitempty"`
+	// Action is "down", "up" or "silence"
+	Action string `json:"action,omitempty"`
+}
+
+// PatternRecognizer is met when the button presses match the pattern
+type PatternRecognizer struct {
+	// Anchor is "start", "end" or "anywhere"
+	Anchor    string    `json:"anchor,omitempty"`
+	Fuzzy     bool      `json:"fuzzy"`
+	GadgetIDs []string  `json:"gadgetIds,omitempty"`
+	Actions   []string  `json:"actions,omitempty"`
+	Pattern   []Pattern `json:"pattern"`
+}
+
+// DeviationRecognizer is met when the button presses can no longer match the named recognizer
+type DeviationRecognizer struct {
+	Recognizer string `json:"recognizer"`
+}
+
+// ProgressRecognizer is met when the named recognizer is completed to the given percentage
+type ProgressRecognizer struct {
+	Recognizer string  `json:"recognizer"`
+	Completion float64 `json:"completion"`
+}
+
+// RecognizerType returns "match"
+func (PatternRecognizer) RecognizerType() string { return "match" }
+
+// RecognizerType returns "deviation"
+func (DeviationRecognizer) RecognizerType() string { return "deviation" }
+
+// RecognizerType returns "progress"
+func (ProgressRecognizer) RecognizerType() string { return "progress" }
+
+// MarshalJSON adds the type of the recognizer
+func (recognizer PatternRecognizer) MarshalJSON() ([]byte, error) {
+	type plain PatternRecognizer
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		plain
+	}{recognizer.RecognizerType(), plain(recognizer)})
+}
+
+// MarshalJSON adds the type of the recognizer
+func (recognizer DeviationRecognizer) MarshalJSON() ([]byte, error) {
+	type plain DeviationRecognizer
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		plain
+	}{recognizer.RecognizerType(), plain(recognizer)})
+}
+
+// MarshalJSON adds the type of the recognizer
+func (recognizer ProgressRecognizer) MarshalJSON() ([]byte, error) {
+	type plain ProgressRecognizer
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		plain
+	}{recognizer.RecognizerType(), plain(recognizer)})
+}
+
+// GameEngineEvent is sent to the skill when all the recognizers of Meets are met and none of Fails,
+// the built-in "timed out" recognizer is met when the input handler times out
+type GameEngineEvent struct {
+	Meets []string `json:"meets"`
+	Fails []string `json:"fails,omitempty"`
+	// Reports is "history", "matches" or "nothing"
+	Reports                 string `json:"reports,omitempty"`
+	ShouldEndInputHandler   bool   `json:"shouldEndInputHandler"`
+	MaximumInvocations      int    `json:"maximumInvocations,omitempty"`
+	TriggerTimeMilliseconds int    `json:"triggerTimeMilliseconds,omitempty"`
+}
+
+// InputHandlerEvent is an event of a GameEngine.InputHandlerEvent request
+type InputHandlerEvent struct {
+	Name        string       `json:"name"`
+	InputEvents []InputEvent `json:"inputEvents"`
+}
+
+// InputEvent is a single button press or release
+type InputEvent struct {
+	GadgetID  string `json:"gadgetId"`
+	Timestamp string `json:"timestamp"`
+	Color     string `json:"color"`
+	Feature   string `json:"feature"`
+	// Action is "down" or "up"
+	Action string `json:"action"`
+}
+
+// GadgetControllerSetLightDirective sends an animation to the lights of Echo Buttons
+type GadgetControllerSetLightDirective struct {
+	Type    string `json:"type"`
+	Version int    `json:"version"`
+	// TargetGadgets are the gadget IDs to animate, all of them when empty
+	TargetGadgets []string           `json:"targetGadgets,omitempty"`
+	Parameters    SetLightParameters `json:"parameters"`
+}
+
+// SetLightParameters describes when and how the lights are animated
+type SetLightParameters struct {
+	// TriggerEvent is "buttonDown", "buttonUp" or "none" (immediately)
+	TriggerEvent       string           `json:"triggerEvent"`
+	TriggerEventTimeMs int              `json:"triggerEventTimeMs"`
+	Animations         []LightAnimation `json:"animations"`
+}
+
+// LightAnimation is a sequence of colors played on the lights of a gadget
+type LightAnimation struct {
+	Repeat       int             `json:"repeat"`
+	TargetLights []string        `json:"targetLights"`
+	Sequence     []AnimationStep `json:"sequence"`
+}
+
+// AnimationStep shows a color (hex RGB, e.g. "FF0000") for the duration, blending from the
+// color of the previous step when Blend is true
+type AnimationStep struct {
+	DurationMs int    `json:"durationMs"`
+	Color      string `json:"color"`
+	Blend      bool   `json:"blend"`
+}
@@ -13,4 +13,46 @@ var (
 	StopIntent = "AMAZON.StopIntent"
 	// RepeatIntent is AMAZON.RepeatIntent
 	RepeatIntent = "AMAZON.RepeatIntent"
+	// FallbackIntent is AMAZON.FallbackIntent
+	FallbackIntent = "AMAZON.FallbackIntent"
+	// NavigateHomeIntent is AMAZON.NavigateHomeIntent
+	NavigateHomeIntent = "AMAZON.NavigateHomeIntent"
+	// NavigateSettingsIntent is AMAZON.NavigateSettingsIntent
+	NavigateSettingsIntent = "AMAZON.NavigateSettingsIntent"
+	// YesIntent is AMAZON.YesIntent
+	YesIntent = "AMAZON.YesIntent"
+	// NoIntent is AMAZON.NoIntent
+	NoIntent = "AMAZON.NoIntent"
+	// NextIntent is AMAZON.NextIntent
+	NextIntent = "AMAZON.NextIntent"
+	// PreviousIntent is AMAZON.PreviousIntent
+	PreviousIntent = "AMAZON.PreviousIntent"
+	// MoreIntent is AMAZON.MoreIntent
+	MoreIntent = "AMAZON.MoreIntent"
+	// ResumeIntent is AMAZON.ResumeIntent
+	ResumeIntent = "AMAZON.ResumeIntent"
+	// LoopOnIntent is AMAZON.LoopOnIntent
+	LoopOnIntent = "AMAZON.LoopOnIntent"
+	// LoopOffIntent is AMAZON.LoopOffIntent
+	LoopOffIntent = "AMAZON.LoopOffIntent"
+	// ShuffleOnIntent is AMAZON.ShuffleOnIntent
+	ShuffleOnIntent = "AMAZON.ShuffleOnIntent"
+	// ShuffleOffIntent is AMAZON.ShuffleOffIntent
+	ShuffleOffIntent = "AMAZON.ShuffleOffIntent"
+	// SelectIntent is AMAZON.SelectIntent
+	SelectIntent = "AMAZON.SelectIntent"
+	// SendToPhoneIntent is AMAZON.SendToPhoneIntent
+	SendToPhoneIntent = "AMAZON.SendToPhoneIntent"
+	// PageUpIntent is AMAZON.PageUpIntent
+	PageUpIntent = "AMAZON.PageUpIntent"
+	// PageDownIntent is AMAZON.PageDownIntent
+	PageDownIntent = "AMAZON.PageDownIntent"
+	// ScrollUpIntent is AMAZON.ScrollUpIntent
+	ScrollUpIntent = "AMAZON.ScrollUpIntent"
+	// ScrollDownIntent is AMAZON.ScrollDownIntent
+	ScrollDownIntent = "AMAZON.ScrollDownIntent"
+	// ScrollLeftIntent is AMAZON.ScrollLeftIntent
+	ScrollLeftIntent = "AMAZON.ScrollLeftIntent"
+	// ScrollRightIntent is AMAZON.ScrollRightIntent
+	ScrollRightIntent = "AMAZON.ScrollRightIntent"
 )
@@ -1,5 +1,7 @@
 package alexa
 
+import "encoding/json"
+
 // RequestEnvelope is the deserialized http post request sent by alexa.
 type RequestEnvelope struct {
 	Version string  `json:"version"`
@@ -33,6 +35,7 @@ type User struct {
 type Context struct {
 	System      System      `json:"System"`
 	AudioPlayer AudioPlayer `json:"audioPlayer"`
+	Viewport    *Viewport   `json:"Viewport,omitempty"`
 }
 
 // System object that provides information about the current state of the Alexa service and the device interacting with your skill.
@@ -42,6 +45,14 @@ type System struct {
 	Application    Application `json:"application"`
 	Device         Device      `json:"device"`
 	User           User        `json:"user"`
+	// Person is the speaker recognized by their voice profile, if any
+	Person *Person `json:"person,omitempty"`
+}
+
+// Person is a speaker recognized by their voice profile.
+type Person struct {
+	PersonID    string `json:"personId"`
+	AccessToken string `json:"accessToken,omitempty"`
 }
 
 // Device object providing information about the device used to send the request.
@@ -81,6 +92,17 @@ type Request struct {
 		RequestID string `json:"requestId"`
 	} `json:"cause"`
 
+	// GameEngine.InputHandlerEvent, the ID of the request that started the input handler
+	OriginatingRequestID string `json:"originatingRequestId,omitempty"`
+	// GameEngine.InputHandlerEvent
+	Events []InputHandlerEvent `json:"events,omitempty"`
+	// CustomInterfaceController.EventsReceived, sent in the "events" of the request
+	CustomInterfaceEvents []CustomInterfaceEvent `json:"-"`
+	// Messaging.MessageReceived, the data sent with the Skill Messaging API
+	Message map[string]interface{} `json:"message,omitempty"`
+	// CustomInterfaceController.Expired
+	ExpirationPayload map[string]interface{} `json:"expirationPayload,omitempty"`
+
 	// AudioPlayerRequest represents an incoming request from the Audioplayer Interface.
 	// It does not have a session context.  Response to such a request must be a
 	// AudioPlayerDirective or empty
@@ -95,6 +117,43 @@ type Request struct {
 	} `json:"currentPlaybackState"`
 }
 
+// customEventsReceived is the request type whose events are custom interface events
+const customEventsReceived = "CustomInterfaceController.EventsReceived"
+
+// UnmarshalJSON decodes the events of CustomInterfaceController.EventsReceived requests into
+// CustomInterfaceEvents rather than Events
+func (request *Request) UnmarshalJSON(data []byte) error {
+	type plain Request
+	if err := json.Unmarshal(data, (*plain)(request)); err != nil {
+		return err
+	}
+	if request.Type != customEventsReceived {
+		return nil
+	}
+
+	var custom struct {
+		Events []CustomInterfaceEvent `json:"events"`
+	}
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return err
+	}
+	request.Events = nil
+	request.CustomInterfaceEvents = custom.Events
+	return nil
+}
+
+// MarshalJSON encodes CustomInterfaceEvents as the events of the request
+func (request Request) MarshalJSON() ([]byte, error) {
+	type plain Request
+	if len(request.CustomInterfaceEvents) == 0 {
+		return json.Marshal(plain(request))
+	}
+	return json.Marshal(struct {
+		plain
+		Events []CustomInterfaceEvent `json:"events"`
+	}{plain(request), request.CustomInterfaceEvents})
+}
+
 // Intent provided in Intent requests
 type Intent struct {
 	Name               string                `json:"name,omitempty"`
@@ -102,10 +161,77 @@ type Intent struct {
 	ConfirmationStatus string                `json:"confirmationStatus,omitempty"`
 }
 
-// IntentSlot is provided in Intents
+// IntentSlot is provided in Intents.  Resolutions holds the entity resolution results as decoded
+// from JSON, or a *Resolutions when set by the model matcher, GetResolutions returns them typed.
 type IntentSlot struct {
 	Name               string      `json:"name"`
 	Value              string      `json:"value"`
 	ConfirmationStatus string      `json:"confirmationStatus,omitempty"`
+	Source             string      `json:"source,omitempty"`
 	Resolutions        interface{} `json:"resolutions"`
 }
+
+// Resolutions contains the results of entity resolution for a slot, grouped by authority.
+type Resolutions struct {
+	ResolutionsPerAuthority []Resolution `json:"resolutionsPerAuthority"`
+}
+
+// Resolution is the result of entity resolution by a single authority (e.g. a custom slot type)
+type Resolution struct {
+	Authority string           `json:"authority"`
+	Status    ResolutionStatus `json:"status"`
+	Values    []ResolutionItem `json:"values,omitempty"`
+}
+
+// ResolutionStatus code is ER_SUCCESS_MATCH, ER_SUCCESS_NO_MATCH, ER_ERROR_TIMEOUT or ER_ERROR_EXCEPTION
+type ResolutionStatus struct {
+	Code string `json:"code"`
+}
+
+// ResolutionItem wraps a resolved value
+type ResolutionItem struct {
+	Value ResolutionValue `json:"value"`
+}
+
+// ResolutionValue is the canonical name and ID of a resolved slot value
+type ResolutionValue struct {
+	Name string `json:"name"`
+	ID   string `json:"id,omitempty"`
+}
+
+// GetResolutions returns the entity resolution results of the slot, or nil
+func (slot IntentSlot) GetResolutions() *Resolutions {
+	switch value := slot.Resolutions.(type) {
+	case nil:
+		return nil
+	case *Resolutions:
+		return value
+	case Resolutions:
+		return &value
+	}
+
+	// Generic JSON values decoded from the request
+	data, err := json.Marshal(slot.Resolutions)
+	if err != nil {
+		return nil
+	}
+	resolutions := &Resolutions{}
+	if err := json.Unmarshal(data, resolutions); err != nil {
+		return nil
+	}
+	return resolutions
+}
+
+// FirstResolvedValue returns the first value matched by entity resolution, if any
+func (slot IntentSlot) FirstResolvedValue() *ResolutionValue {
+	resolutions := slot.GetResolutions()
+	if resolutions == nil {
+		return nil
+	}
+	for _, resolution := range resolutions.ResolutionsPerAuthority {
+		if resolution.Status.Code == "ER_SUCCESS_MATCH" && len(resolution.Values) != 0 {
+			return &resolution.Values[0].Value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package alexa
+
+// Viewport object describes the characteristics of the screen of the device making the request.
+// It is only present when the device supports the Alexa.Presentation.APL interface.
+type Viewport struct {
+	Experiences []ViewportExperience `json:"experiences,omitempty"`
+	// Shape of the viewport (e.g. 'RECTANGLE' or 'ROUND')
+	Shape              string         `json:"shape"`
+	PixelWidth         int            `json:"pixelWidth"`
+	PixelHeight        int            `json:"pixelHeight"`
+	CurrentPixelWidth  int            `json:"currentPixelWidth"`
+	CurrentPixelHeight int            `json:"currentPixelHeight"`
+	DPI                int            `json:"dpi"`
+	Touch              []string       `json:"touch,omitempty"`
+	Keyboard           []string       `json:"keyboard,omitempty"`
+	Video              *ViewportVideo `json:"video,omitempty"`
+}
+
+// ViewportExperience describes one of the ways the device may be viewed by the user.
+type ViewportExperience struct {
+	ArcMinuteWidth  float64 `json:"arcMinuteWidth"`
+	ArcMinuteHeight float64 `json:"arcMinuteHeight"`
+	CanRotate       bool    `json:"canRotate"`
+	CanResize       bool    `json:"canResize"`
+}
+
+// ViewportVideo lists the video codecs supported by the viewport.
+type ViewportVideo struct {
+	Codecs []string `json:"codecs,omitempty"`
+}
@@ -0,0 +1,103 @@
+package askgotest_test
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/koblas/askgo/askgotest"
+	"github.com/koblas/askgo/model"
+	"github.com/stretchr/testify/require"
+)
+
+func counterSkill() *askgo.Skill {
+	count := func(input askgo.HandlerInput) int {
+		value, _ := input.GetRequestEnvelope().Session.Attributes["count"].(float64)
+		return int(value)
+	}
+
+	return &askgo.Skill{
+		Handlers: []askgo.RequestHandler{
+			askgo.RequestRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				response := input.GetResponse().Speak("<speak>Welcome to the <emphasis>counter</emphasis>!</speak>").Reprompt("Say count.")
+				response.SessionAttributes = map[string]interface{}{"count": 0}
+				return response, nil
+			}, "LaunchRequest"),
+			askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				by := 1
+				if slot, ok := input.GetRequest().Intent.Slots["By"]; ok && slot.Value != "" {
+					fmt.Sscan(slot.Value, &by)
+				}
+				response := input.GetResponse().Speak(fmt.Sprintf("The count is %d", count(input)+by))
+				response.SessionAttributes = map[string]interface{}{"count": count(input) + by}
+				return response, nil
+			}, "CountIntent"),
+			askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				return input.GetResponse().Speak("Bye").WithSimpleCard("Counter", fmt.Sprintf("Final count %d", count(input))).WithShouldEndSession(true), nil
+			}, alexa.StopIntent),
+			askgo.RequestRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				return input.GetResponse().AddAudioPlayerStopDirective(), nil
+			}, "AudioPlayer.PlaybackStarted"),
+		},
+	}
+}
+
+func Test_NormalizeSpeech(t *testing.T) {
+	require.Equal(t, "Hello there & welcome", askgotest.NormalizeSpeech("<speak>Hello <break time='1s'/>there\n &amp; welcome </speak>"))
+}
+
+func Test_Script(t *testing.T) {
+	schema, err := model.Load(strings.NewReader(`{"interactionModel": {"languageModel": {
+		"invocationName": "counter",
+		"intents": [
+			{"name": "AMAZON.StopIntent", "samples": []},
+			{"name": "CountIntent", "samples": ["count", "count by {By}"], "slots": [{"name": "By", "type": "AMAZON.NUMBER"}]}
+		]
+	}}}`))
+	require.NoError(t, err)
+	matcher, err := model.NewMatcher(schema)
+	require.NoError(t, err)
+
+	conv := askgotest.NewConversation(counterSkill())
+	conv.Matcher = matcher
+
+	conv.Run(t, askgotest.Script{
+		{Launch: true, Expect: askgotest.Expect{Speech: "Welcome to the counter!", Reprompt: "Say count.", ShouldEndSession: askgotest.Bool(false)}},
+		{Intent: "CountIntent", Expect: askgotest.Expect{Speech: "The count is 1", SessionAttributes: map[string]interface{}{"count": 1}}},
+		{Say: "count by 5", Expect: askgotest.Expect{SpeechContains: "is 6", NoReprompt: true}},
+		{Request: askgotest.NewAudioPlayerRequest("AudioPlayer.PlaybackStarted", "track", 0), Expect: askgotest.Expect{DirectiveTypes: []string{"AudioPlayer.Stop"}}},
+		{Say: "stop", Expect: askgotest.Expect{Speech: "Bye", CardContains: "Final count 6", ShouldEndSession: askgotest.Bool(true)}},
+	})
+
+	require.False(t, conv.InSession())
+
+	envelope := conv.Prepare(askgotest.NewLaunchRequest())
+	require.True(t, envelope.Session.New)
+	require.Nil(t, envelope.Session.Attributes)
+
+	// The attributes of the envelope are kept over those of the conversation
+	envelope = askgotest.NewIntentRequest("CountIntent", nil)
+	envelope.Session.Attributes = map[string]interface{}{"count": 41.0}
+	response, err := conv.Send(envelope)
+	require.NoError(t, err)
+	require.Equal(t, "The count is 42", askgotest.Speech(response))
+
+	// A turn without a request is an error rather than the end of the session
+	_, err = conv.SendTurn(askgotest.Turn{Expect: askgotest.Expect{Speech: "Bye"}})
+	require.ErrorIs(t, err, askgotest.ErrEmptyTurn)
+	require.True(t, conv.InSession())
+}
+
+func Test_Golden(t *testing.T) {
+	golden := &askgotest.Golden{
+		Skill:        counterSkill(),
+		Dir:          "testdata/golden",
+		Mask:         []string{"response.card.content"},
+		MaskPatterns: []*regexp.Regexp{regexp.MustCompile(`<emphasis>[^<]*</emphasis>`)},
+	}
+
+	golden.Run(t)
+}
@@ -0,0 +1,262 @@
+package askgotest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/koblas/askgo/model"
+)
+
+// Conversation sends requests to a skill as a single user on a single device,
+// carrying the session attributes returned by each response into the next request.
+type Conversation struct {
+	Skill *askgo.Skill
+	// Context is passed to the skill, defaults to context.Background()
+	Context context.Context
+
+	// Locale of the requests, defaults to en-US
+	Locale        string
+	ApplicationID string
+	UserID        string
+	DeviceID      string
+	// AccessToken is the account linking access token of the user, empty for an unlinked account
+	AccessToken string
+	// PersonID is the speaker recognized by voice, empty when not recognized
+	PersonID string
+	// PersonAccessToken is the account linking access token of the recognized speaker
+	PersonAccessToken string
+	// APIAccessToken and APIEndpoint are sent in the System context
+	APIAccessToken string
+	APIEndpoint    string
+	// SupportedInterfaces of the device (e.g. {"Display": {}, "AudioPlayer": {}})
+	SupportedInterfaces map[string]interface{}
+	// Viewport of the device, nil for devices without a screen
+	Viewport *alexa.Viewport
+
+	// Matcher is used to resolve utterances passed to Say
+	Matcher *model.Matcher
+
+	sessionID  string
+	newSession bool
+	attributes map[string]interface{}
+	audio      alexa.AudioPlayer
+	counter    int
+}
+
+// NewConversation starts a conversation with the skill
+func NewConversation(skill *askgo.Skill) *Conversation {
+	return &Conversation{
+		Skill:          skill,
+		Locale:         "en-US",
+		ApplicationID:  skill.ApplicationID,
+		UserID:         "amzn1.ask.account.test-user",
+		DeviceID:       "amzn1.ask.device.test-device",
+		APIAccessToken: "test-api-access-token",
+		APIEndpoint:    "https://api.amazonalexa.com",
+	}
+}
+
+// SessionAttributes returns the session attributes that will be sent with the next request
+func (conv *Conversation) SessionAttributes() map[string]interface{} {
+	return conv.attributes
+}
+
+// SetSessionAttributes replaces the session attributes sent with the next request
+func (conv *Conversation) SetSessionAttributes(attributes map[string]interface{}) {
+	conv.attributes = attributes
+}
+
+// InSession reports whether the next request continues an existing session
+func (conv *Conversation) InSession() bool {
+	return conv.sessionID != ""
+}
+
+// Reset ends the current session without sending a request
+func (conv *Conversation) Reset() {
+	conv.sessionID = ""
+	conv.attributes = nil
+}
+
+// Launch sends a LaunchRequest
+func (conv *Conversation) Launch() (*askgo.ResponseEnvelope, error) {
+	return conv.Send(NewLaunchRequest())
+}
+
+// Intent sends an IntentRequest with the given slot values
+func (conv *Conversation) Intent(name string, slots map[string]string) (*askgo.ResponseEnvelope, error) {
+	return conv.Send(NewIntentRequest(name, slots))
+}
+
+// Say resolves the utterance with the Matcher and sends the resulting IntentRequest
+func (conv *Conversation) Say(utterance string) (*askgo.ResponseEnvelope, error) {
+	if conv.Matcher == nil {
+		return nil, errors.New("askgotest: a Matcher is required to resolve utterances")
+	}
+	intent, err := conv.Matcher.Match(utterance)
+	if err != nil {
+		return nil, fmt.Errorf("askgotest: %q: %v", utterance, err)
+	}
+	return conv.Send(NewIntent(intent))
+}
+
+// End sends a SessionEndedRequest
+func (conv *Conversation) End(reason string) (*askgo.ResponseEnvelope, error) {
+	return conv.Send(NewSessionEndedRequest(reason))
+}
+
+// Prepare fills in the session, context and identifiers of the envelope as the
+// next request of the conversation, without sending it.  The request ID, timestamp and locale
+// are kept when set.  The session and the system, audio player and viewport of the context are
+// replaced by those of the conversation, except for the session attributes of the envelope
+// which are set over the attributes of the conversation.
+func (conv *Conversation) Prepare(envelope *askgo.RequestEnvelope) *askgo.RequestEnvelope {
+	conv.counter++
+
+	if envelope.Version == "" {
+		envelope.Version = "1.0"
+	}
+
+	request := &envelope.Request
+	if request.RequestID == "" {
+		request.RequestID = fmt.Sprintf("amzn1.echo-api.request.test-%d", conv.counter)
+	}
+	if request.Timestamp == "" {
+		request.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+	if request.Locale == "" {
+		request.Locale = conv.Locale
+	}
+
+	application := alexa.Application{ApplicationID: conv.ApplicationID}
+	user := alexa.User{UserID: conv.UserID, AccessToken: conv.AccessToken}
+
+	// AudioPlayer and PlaybackController requests are sent outside of the session
+	if !isOutOfSession(request.Type) {
+		if conv.sessionID == "" {
+			conv.sessionID = fmt.Sprintf("amzn1.echo-api.session.test-%d", conv.counter)
+			conv.newSession = true
+			conv.attributes = nil
+		}
+		attributes := copyAttributes(conv.attributes)
+		if len(envelope.Session.Attributes) != 0 && attributes == nil {
+			attributes = make(map[string]interface{}, len(envelope.Session.Attributes))
+		}
+		for key, value := range envelope.Session.Attributes {
+			attributes[key] = value
+		}
+		envelope.Session = alexa.Session{
+			New:         conv.newSession,
+			SessionID:   conv.sessionID,
+			Attributes:  attributes,
+			Application: application,
+			User:        user,
+		}
+	}
+
+	envelope.Context.System = alexa.System{
+		APIAccessToken: conv.APIAccessToken,
+		APIEndpoint:    conv.APIEndpoint,
+		Application:    application,
+		User:           user,
+		Device: alexa.Device{
+			DeviceID:            conv.DeviceID,
+			SupportedInterfaces: conv.SupportedInterfaces,
+		},
+	}
+	if conv.PersonID != "" {
+		envelope.Context.System.Person = &alexa.Person{PersonID: conv.PersonID, AccessToken: conv.PersonAccessToken}
+	}
+	envelope.Context.AudioPlayer = conv.audio
+	envelope.Context.Viewport = conv.Viewport
+
+	return envelope
+}
+
+// Send sends the envelope to the skill as the next request of the conversation
+func (conv *Conversation) Send(envelope *askgo.RequestEnvelope) (*askgo.ResponseEnvelope, error) {
+	envelope = conv.Prepare(envelope)
+
+	ctx := conv.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	result, err := conv.Skill.ProcessRequest(askgo.NewDefaultHandler(ctx, envelope))
+	response, _ := result.(*askgo.ResponseEnvelope)
+
+	conv.update(envelope, response)
+
+	return response, err
+}
+
+// update the state of the conversation from the response
+func (conv *Conversation) update(envelope *askgo.RequestEnvelope, response *askgo.ResponseEnvelope) {
+	request := envelope.Request
+
+	if strings.HasPrefix(request.Type, "AudioPlayer.") {
+		conv.audio.Token = request.Token
+		conv.audio.OffsetInMilliseconds = request.OffsetInMilliseconds
+		switch request.Type {
+		case "AudioPlayer.PlaybackStarted":
+			conv.audio.PlayerActivity = "PLAYING"
+		case "AudioPlayer.PlaybackStopped":
+			conv.audio.PlayerActivity = "STOPPED"
+		case "AudioPlayer.PlaybackFinished":
+			conv.audio.PlayerActivity = "FINISHED"
+		case "AudioPlayer.PlaybackFailed":
+			conv.audio.PlayerActivity = "STOPPED"
+		}
+	}
+
+	if isOutOfSession(request.Type) {
+		return
+	}
+
+	conv.newSession = false
+
+	if request.Type == "SessionEndedRequest" || response == nil || response.Response == nil ||
+		response.Response.ShouldSessionEnd {
+		conv.Reset()
+		return
+	}
+
+	// Alexa only returns what the skill sent, as JSON
+	conv.attributes = roundTrip(response.SessionAttributes)
+}
+
+func isOutOfSession(requestType string) bool {
+	return strings.HasPrefix(requestType, "AudioPlayer.") || strings.HasPrefix(requestType, "PlaybackController.")
+}
+
+func copyAttributes(attributes map[string]interface{}) map[string]interface{} {
+	if attributes == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(attributes))
+	for k, v := range attributes {
+		result[k] = v
+	}
+	return result
+}
+
+// roundTrip converts the attributes to the types produced by decoding JSON
+func roundTrip(attributes map[string]interface{}) map[string]interface{} {
+	if attributes == nil {
+		return nil
+	}
+	data, err := json.Marshal(attributes)
+	if err != nil {
+		return attributes
+	}
+	result := make(map[string]interface{})
+	if err := json.Unmarshal(data, &result); err != nil {
+		return attributes
+	}
+	return result
+}
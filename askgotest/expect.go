@@ -0,0 +1,293 @@
+package askgotest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+)
+
+var (
+	ssmlTagRe  = regexp.MustCompile(`<[^>]*>`)
+	ssmlAudio  = regexp.MustCompile(`<audio[^>]*>`)
+	ssmlBreaks = regexp.MustCompile(`<break[^>]*>`)
+)
+
+// NormalizeSpeech strips the SSML markup from the speech and collapses whitespace,
+// so that responses can be compared with the text a user would hear
+func NormalizeSpeech(ssml string) string {
+	text := ssmlAudio.ReplaceAllString(ssml, " ")
+	text = ssmlBreaks.ReplaceAllString(text, " ")
+	text = ssmlTagRe.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// Speech returns the normalized output speech of the response
+func Speech(response *askgo.ResponseEnvelope) string {
+	if response == nil || response.Response == nil || response.Response.OutputSpeech == nil {
+		return ""
+	}
+	speech := response.Response.OutputSpeech
+	if speech.Type == "SSML" {
+		return NormalizeSpeech(speech.SSML)
+	}
+	return NormalizeSpeech(speech.Text)
+}
+
+// Reprompt returns the normalized reprompt speech of the response
+func Reprompt(response *askgo.ResponseEnvelope) string {
+	if response == nil || response.Response == nil || response.Response.Reprompt == nil {
+		return ""
+	}
+	speech := response.Response.Reprompt.OutputSpeech
+	if speech == nil {
+		return ""
+	}
+	if speech.Type == "SSML" {
+		return NormalizeSpeech(speech.SSML)
+	}
+	return NormalizeSpeech(speech.Text)
+}
+
+// DirectiveTypes returns the type of each directive of the response
+func DirectiveTypes(response *askgo.ResponseEnvelope) []string {
+	types := make([]string, 0)
+	if response == nil || response.Response == nil {
+		return types
+	}
+
+	for _, directive := range response.Response.Directives {
+		var header struct {
+			Type string `json:"type"`
+		}
+		if data, err := json.Marshal(directive); err == nil {
+			json.Unmarshal(data, &header)
+		}
+		types = append(types, header.Type)
+	}
+	return types
+}
+
+// Bool returns a pointer to the value, for use in Expect.ShouldEndSession
+func Bool(value bool) *bool {
+	return &value
+}
+
+// Expect describes the expected response, empty fields are not checked.
+// Speech comparisons are made on the normalized speech.
+type Expect struct {
+	// Error is true when the skill is expected to return an error
+	Error bool
+
+	Speech           string
+	SpeechContains   string
+	Reprompt         string
+	RepromptContains string
+	// NoReprompt requires the response to have no reprompt
+	NoReprompt bool
+
+	CardTitle        string
+	CardContains     string
+	CardType         string
+	NoCard           bool
+	DirectiveTypes   []string
+	NoDirectives     bool
+	ShouldEndSession *bool
+
+	// SessionAttributes that must be present in the response with the given (JSON) values
+	SessionAttributes map[string]interface{}
+}
+
+// Check returns a description of every way the response differs from the expectation
+func (expect Expect) Check(response *askgo.ResponseEnvelope, err error) []string {
+	failures := make([]string, 0)
+	fail := func(format string, args ...interface{}) {
+		failures = append(failures, fmt.Sprintf(format, args...))
+	}
+
+	if expect.Error {
+		if err == nil {
+			fail("expected an error")
+		}
+		return failures
+	}
+	if err != nil {
+		fail("unexpected error: %v", err)
+		return failures
+	}
+	if response == nil {
+		fail("expected a response, got nil")
+		return failures
+	}
+
+	speech := Speech(response)
+	if expect.Speech != "" && speech != NormalizeSpeech(expect.Speech) {
+		fail("speech: expected %q, got %q", NormalizeSpeech(expect.Speech), speech)
+	}
+	if expect.SpeechContains != "" && !strings.Contains(speech, NormalizeSpeech(expect.SpeechContains)) {
+		fail("speech: expected to contain %q, got %q", expect.SpeechContains, speech)
+	}
+
+	reprompt := Reprompt(response)
+	if expect.Reprompt != "" && reprompt != NormalizeSpeech(expect.Reprompt) {
+		fail("reprompt: expected %q, got %q", NormalizeSpeech(expect.Reprompt), reprompt)
+	}
+	if expect.RepromptContains != "" && !strings.Contains(reprompt, NormalizeSpeech(expect.RepromptContains)) {
+		fail("reprompt: expected to contain %q, got %q", expect.RepromptContains, reprompt)
+	}
+	if expect.NoReprompt && reprompt != "" {
+		fail("reprompt: expected none, got %q", reprompt)
+	}
+
+	body := response.Response
+	var card *alexa.Card
+	if body != nil {
+		card = body.Card
+	}
+	switch {
+	case expect.NoCard && card != nil:
+		fail("card: expected none, got %q", card.Title)
+	case (expect.CardTitle != "" || expect.CardType != "" || expect.CardContains != "") && card == nil:
+		fail("card: expected a card")
+	case card != nil:
+		if expect.CardTitle != "" && card.Title != expect.CardTitle {
+			fail("card title: expected %q, got %q", expect.CardTitle, card.Title)
+		}
+		if expect.CardType != "" && card.Type != expect.CardType {
+			fail("card type: expected %q, got %q", expect.CardType, card.Type)
+		}
+		if expect.CardContains != "" && !strings.Contains(card.Content+card.Text, expect.CardContains) {
+			fail("card: expected to contain %q, got %q", expect.CardContains, card.Content+card.Text)
+		}
+	}
+
+	types := DirectiveTypes(response)
+	for _, expected := range expect.DirectiveTypes {
+		found := false
+		for _, actual := range types {
+			if actual == expected {
+				found = true
+			}
+		}
+		if !found {
+			fail("directives: expected %s, got %v", expected, types)
+		}
+	}
+	if expect.NoDirectives && len(types) != 0 {
+		fail("directives: expected none, got %v", types)
+	}
+
+	if expect.ShouldEndSession != nil {
+		ended := body != nil && body.ShouldSessionEnd
+		if ended != *expect.ShouldEndSession {
+			fail("shouldEndSession: expected %v, got %v", *expect.ShouldEndSession, ended)
+		}
+	}
+
+	if len(expect.SessionAttributes) != 0 {
+		actual := roundTrip(response.SessionAttributes)
+		expected := roundTrip(expect.SessionAttributes)
+		for key, value := range expected {
+			if !reflect.DeepEqual(actual[key], value) {
+				fail("session attribute %s: expected %v, got %v", key, value, actual[key])
+			}
+		}
+	}
+
+	return failures
+}
+
+// Assert reports the differences between the response and the expectation as test errors
+func (expect Expect) Assert(t testing.TB, response *askgo.ResponseEnvelope, err error) bool {
+	t.Helper()
+
+	failures := expect.Check(response, err)
+	for _, failure := range failures {
+		t.Error(failure)
+	}
+	return len(failures) == 0
+}
+
+// Turn is a single request of a script and the expected response.  Exactly one of
+// Request, Launch, Intent, Say or End should be given.
+type Turn struct {
+	Request *askgo.RequestEnvelope
+	Launch  bool
+	Intent  string
+	Slots   map[string]string
+	// Say is resolved using the Matcher of the conversation
+	Say string
+	// End sends a SessionEndedRequest with the given reason (e.g. "USER_INITIATED")
+	End string
+
+	Expect Expect
+}
+
+func (turn Turn) String() string {
+	switch {
+	case turn.Request != nil:
+		return turn.Request.Request.Type
+	case turn.Launch:
+		return "LaunchRequest"
+	case turn.Intent != "":
+		return turn.Intent
+	case turn.Say != "":
+		return fmt.Sprintf("%q", turn.Say)
+	case turn.End != "":
+		return "SessionEndedRequest " + turn.End
+	}
+	return "empty turn"
+}
+
+// ErrEmptyTurn is returned by SendTurn for a turn without a request, intent, utterance or
+// end reason, rather than ending the session
+var ErrEmptyTurn = errors.New("askgotest: the turn has no request, intent, utterance or end reason")
+
+// Script is a multi-turn conversation with a skill
+type Script []Turn
+
+// SendTurn sends the request described by the turn
+func (conv *Conversation) SendTurn(turn Turn) (*askgo.ResponseEnvelope, error) {
+	switch {
+	case turn.Request != nil:
+		return conv.Send(turn.Request)
+	case turn.Launch:
+		return conv.Launch()
+	case turn.Intent != "":
+		return conv.Intent(turn.Intent, turn.Slots)
+	case turn.Say != "":
+		return conv.Say(turn.Say)
+	case turn.End != "":
+		return conv.End(turn.End)
+	}
+	return nil, ErrEmptyTurn
+}
+
+// Run sends each turn of the script in order, checking the responses.  The script
+// stops at the first turn that doesn't meet its expectation.
+func (conv *Conversation) Run(t testing.TB, script Script) {
+	t.Helper()
+
+	for idx, turn := range script {
+		response, err := conv.SendTurn(turn)
+		if errors.Is(err, ErrEmptyTurn) {
+			t.Errorf("turn %d: %v", idx+1, err)
+			return
+		}
+		failures := turn.Expect.Check(response, err)
+		for _, failure := range failures {
+			t.Errorf("turn %d (%s): %s", idx+1, turn, failure)
+		}
+		if len(failures) != 0 {
+			return
+		}
+	}
+}
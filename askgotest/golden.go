@@ -0,0 +1,236 @@
+package askgotest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// UpdateEnvVar rewrites the golden files of every Golden when set to a non-empty value
+// (e.g. ASKGO_UPDATE_GOLDEN=1 go test ./...)
+const UpdateEnvVar = "ASKGO_UPDATE_GOLDEN"
+
+// MaskedValue replaces masked values in golden files
+const MaskedValue = "<masked>"
+
+// Golden records the response produced for each request fixture of a directory and
+// compares later runs against the recording.
+//
+// Every *.json file of Dir (other than the golden files) is a RequestEnvelope, its response
+// is stored in <name>.golden.json.  Set Update, or the UpdateEnvVar environment variable, to
+// rewrite the golden files.
+// Request timestamps are not verified so fixtures don't expire.
+type Golden struct {
+	Skill *askgo.Skill
+	Dir   string
+
+	// Mask are the paths of values replaced by MaskedValue, the path is the dotted list of JSON keys
+	// from the response envelope, where * matches any key or array index
+	// (e.g. "response.directives.*.template.token" or "sessionAttributes.QuizItemIndex")
+	Mask []string
+	// MaskPatterns replace matching text within every string value by MaskedValue
+	// (e.g. the interjection of a randomly chosen speechcon)
+	MaskPatterns []*regexp.Regexp
+
+	// Update rewrites the golden files rather than comparing against them
+	Update bool
+}
+
+const goldenSuffix = ".golden.json"
+
+// Run checks every fixture as a subtest
+func (golden *Golden) Run(t *testing.T) {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(golden.Dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file, goldenSuffix) {
+			continue
+		}
+		file := file
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		t.Run(name, func(t *testing.T) {
+			golden.check(t, file)
+		})
+	}
+}
+
+func (golden *Golden) check(t *testing.T, file string) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope := &askgo.RequestEnvelope{}
+	if err := json.Unmarshal(data, envelope); err != nil {
+		t.Fatalf("%s: %v", file, err)
+	}
+
+	actual, err := golden.Render(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goldenFile := strings.TrimSuffix(file, ".json") + goldenSuffix
+	if golden.Update || os.Getenv(UpdateEnvVar) != "" {
+		if err := os.WriteFile(goldenFile, actual, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(goldenFile)
+	if os.IsNotExist(err) {
+		t.Fatalf("%s does not exist, run with %s=1 to create it", goldenFile, UpdateEnvVar)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(expected) != string(actual) {
+		diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(expected)),
+			B:        difflib.SplitLines(string(actual)),
+			FromFile: goldenFile,
+			ToFile:   "actual",
+			Context:  3,
+		})
+		t.Errorf("response differs from golden file:\n%s", diff)
+	}
+}
+
+// Render processes the request and returns the canonical JSON for the response
+func (golden *Golden) Render(envelope *askgo.RequestEnvelope) ([]byte, error) {
+	skill := *golden.Skill
+	skill.IgnoreTimestamp = true
+
+	result, err := skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), envelope))
+
+	output := make(map[string]interface{})
+	if err != nil {
+		output["error"] = err.Error()
+	}
+	if response, ok := result.(*askgo.ResponseEnvelope); ok && response != nil {
+		tree, err := golden.canonical(response)
+		if err != nil {
+			return nil, err
+		}
+		output["response"] = tree
+	}
+
+	// Maps are marshalled with sorted keys, which orders the session attributes.
+	// SSML is kept readable by not escaping HTML characters.
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// canonical converts the response to a JSON tree with sorted directives and masked values
+func (golden *Golden) canonical(response *askgo.ResponseEnvelope) (interface{}, error) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+
+	if root, ok := tree.(map[string]interface{}); ok {
+		if body, ok := root["response"].(map[string]interface{}); ok {
+			if directives, ok := body["directives"].([]interface{}); ok {
+				sortByJSON(directives)
+			}
+		}
+	}
+
+	for _, path := range golden.Mask {
+		tree = maskPath(tree, strings.Split(path, "."))
+	}
+	if len(golden.MaskPatterns) != 0 {
+		tree = maskPatterns(tree, golden.MaskPatterns)
+	}
+
+	return tree, nil
+}
+
+func sortByJSON(items []interface{}) {
+	keys := make(map[int]string, len(items))
+	for idx, item := range items {
+		data, _ := json.Marshal(item)
+		keys[idx] = string(data)
+	}
+	indexes := make([]int, len(items))
+	for idx := range indexes {
+		indexes[idx] = idx
+	}
+	sort.SliceStable(indexes, func(i, j int) bool { return keys[indexes[i]] < keys[indexes[j]] })
+
+	sorted := make([]interface{}, len(items))
+	for idx, from := range indexes {
+		sorted[idx] = items[from]
+	}
+	copy(items, sorted)
+}
+
+func maskPath(node interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		if node == nil {
+			return nil
+		}
+		return MaskedValue
+	}
+
+	key, rest := path[0], path[1:]
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for k, child := range value {
+			if key == "*" || key == k {
+				value[k] = maskPath(child, rest)
+			}
+		}
+	case []interface{}:
+		for idx, child := range value {
+			if key == "*" || key == strconv.Itoa(idx) {
+				value[idx] = maskPath(child, rest)
+			}
+		}
+	}
+	return node
+}
+
+func maskPatterns(node interface{}, patterns []*regexp.Regexp) interface{} {
+	switch value := node.(type) {
+	case string:
+		for _, pattern := range patterns {
+			value = pattern.ReplaceAllLiteralString(value, MaskedValue)
+		}
+		return value
+	case map[string]interface{}:
+		for k, child := range value {
+			value[k] = maskPatterns(child, patterns)
+		}
+	case []interface{}:
+		for idx, child := range value {
+			value[idx] = maskPatterns(child, patterns)
+		}
+	}
+	return node
+}
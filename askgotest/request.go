@@ -0,0 +1,81 @@
+// Package askgotest provides utilities for testing skills built with askgo.
+//
+// Request envelopes are built with the New* functions and sent through a skill with a
+// Conversation, which fills in the session, user and device and carries the session
+// attributes from one turn to the next, just like Alexa does.
+//
+//	conv := askgotest.NewConversation(skill)
+//	conv.Run(t, askgotest.Script{
+//		{Launch: true, Expect: askgotest.Expect{SpeechContains: "Welcome"}},
+//		{Intent: "QuizIntent", Expect: askgotest.Expect{RepromptContains: "question"}},
+//		{Say: "stop", Expect: askgotest.Expect{ShouldEndSession: askgotest.Bool(true)}},
+//	})
+package askgotest
+
+import (
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+)
+
+// NewRequest returns an envelope for a request of the given type, the session,
+// context and identifiers are filled in when it is sent by a Conversation
+func NewRequest(requestType string) *askgo.RequestEnvelope {
+	return &askgo.RequestEnvelope{
+		Version: "1.0",
+		Request: askgo.Request{Type: requestType},
+	}
+}
+
+// NewLaunchRequest returns a LaunchRequest envelope
+func NewLaunchRequest() *askgo.RequestEnvelope {
+	return NewRequest("LaunchRequest")
+}
+
+// NewIntentRequest returns an IntentRequest envelope for the intent with the given slot values.
+// Every slot is given a confirmation status of NONE.
+func NewIntentRequest(name string, slots map[string]string) *askgo.RequestEnvelope {
+	intent := alexa.Intent{
+		Name:               name,
+		ConfirmationStatus: "NONE",
+		Slots:              make(map[string]alexa.IntentSlot),
+	}
+	for slotName, value := range slots {
+		intent.Slots[slotName] = alexa.IntentSlot{
+			Name:               slotName,
+			Value:              value,
+			ConfirmationStatus: "NONE",
+		}
+	}
+
+	return NewIntent(intent)
+}
+
+// NewIntent returns an IntentRequest envelope for a fully specified intent (e.g. from model.Matcher)
+func NewIntent(intent alexa.Intent) *askgo.RequestEnvelope {
+	envelope := NewRequest("IntentRequest")
+	envelope.Request.Intent = intent
+	envelope.Request.DialogState = "STARTED"
+	return envelope
+}
+
+// NewSessionEndedRequest returns a SessionEndedRequest envelope, the reason is
+// USER_INITIATED, ERROR or EXCEEDED_MAX_REPROMPTS
+func NewSessionEndedRequest(reason string) *askgo.RequestEnvelope {
+	envelope := NewRequest("SessionEndedRequest")
+	envelope.Request.Reason = reason
+	return envelope
+}
+
+// NewAudioPlayerRequest returns an AudioPlayer event envelope (e.g. AudioPlayer.PlaybackStarted)
+func NewAudioPlayerRequest(eventType, token string, offsetInMilliseconds int) *askgo.RequestEnvelope {
+	envelope := NewRequest(eventType)
+	envelope.Request.Token = token
+	envelope.Request.OffsetInMilliseconds = offsetInMilliseconds
+	return envelope
+}
+
+// NewPlaybackControllerRequest returns a PlaybackController envelope, the command
+// is one of NextCommandIssued, PauseCommandIssued, PlayCommandIssued or PreviousCommandIssued
+func NewPlaybackControllerRequest(command string) *askgo.RequestEnvelope {
+	return NewRequest("PlaybackController." + command)
+}
@@ -0,0 +1,77 @@
+// Command askgo-sim runs a skill locally in an interactive simulator.
+//
+// The main package of the skill must call sim.Main(skill) before starting the Lambda
+// handler, askgo-sim builds and runs that package with the askgosim tag, which enables the
+// simulator:
+//
+//	askgo-sim -model models/en-US.json -device display ./cmd/skill
+//
+// Type utterances to send them to the skill, or /help for the list of commands.
+// With -replay the requests recorded by the record package are replayed instead:
+//
+//	askgo-sim -replay recordings/ ./cmd/skill
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/koblas/askgo/sim"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: askgo-sim [flags] [package]\n")
+		flag.PrintDefaults()
+	}
+	locale := flag.String("locale", "en-US", "locale of the requests")
+	modelFile := flag.String("model", "", "interaction model JSON used to resolve utterances")
+	user := flag.String("user", "", "user ID of the requests")
+	device := flag.String("device", "", "device capabilities (audio, display, video, apl)")
+	viewport := flag.String("viewport", "", "device viewport as WIDTHxHEIGHT[@DPI]")
+	replay := flag.String("replay", "", "replay the recorded requests of a file or directory and diff the responses")
+	flag.Parse()
+
+	pkg := "."
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	} else if flag.NArg() == 1 {
+		pkg = flag.Arg(0)
+	}
+
+	args := []string{"-locale", *locale}
+	if *modelFile != "" {
+		args = append(args, "-model", *modelFile)
+	}
+	if *user != "" {
+		args = append(args, "-user", *user)
+	}
+	if *device != "" {
+		args = append(args, "-device", *device)
+	}
+	if *viewport != "" {
+		args = append(args, "-viewport", *viewport)
+	}
+	if *replay != "" {
+		args = append(args, "-replay", *replay)
+	}
+	encoded, _ := json.Marshal(args)
+
+	cmd := exec.Command("go", "run", "-tags", "askgosim", pkg)
+	cmd.Env = append(os.Environ(), sim.EnvVar+"="+string(encoded))
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if exit, ok := err.(*exec.ExitError); ok {
+			os.Exit(exit.ExitCode())
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,160 @@
+package askgo
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// ErrNoHandler is matched (with errors.Is) by the error returned when no request handler
+// can handle a request and the skill has no FallbackHandler
+var ErrNoHandler = errors.New("askgo: no request handler can handle the request")
+
+// NoHandlerError is passed to the error handlers when no request handler can handle a request
+type NoHandlerError struct {
+	RequestType string
+	Intent      string
+	// Consulted are the names of the request handlers that were asked, in order
+	Consulted []string
+}
+
+func (err *NoHandlerError) Error() string {
+	request := err.RequestType
+	if err.Intent != "" {
+		request += " " + err.Intent
+	}
+	return fmt.Sprintf("askgo: no request handler can handle %s (consulted: %s)", request, strings.Join(err.Consulted, ", "))
+}
+
+// Is matches ErrNoHandler
+func (err *NoHandlerError) Is(target error) bool {
+	return target == ErrNoHandler
+}
+
+// PanicError is a panic recovered from a handler or interceptor
+type PanicError struct {
+	Value interface{}
+	// Stack is the stack trace of the goroutine at the time of the panic
+	Stack []byte
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("askgo: panic: %v", err.Value)
+}
+
+// Unwrap returns the panic value when it is an error
+func (err *PanicError) Unwrap() error {
+	if cause, ok := err.Value.(error); ok {
+		return cause
+	}
+	return nil
+}
+
+// Capture calls fn, turning a panic into a PanicError
+func Capture(fn func() error) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = &PanicError{Value: value, Stack: debug.Stack()}
+		}
+	}()
+	return fn()
+}
+
+// VerificationError is passed to the error handlers for requests failing the application ID or
+// timestamp verification, the interceptors and request handlers are not called
+type VerificationError struct {
+	Err error
+}
+
+func (err *VerificationError) Error() string {
+	return "askgo: request verification failed: " + err.Err.Error()
+}
+
+// Unwrap returns the reason of the failure
+func (err *VerificationError) Unwrap() error {
+	return err.Err
+}
+
+// DecodeError is passed to the error handlers by Skill.Invoke when the payload is not a
+// request envelope
+type DecodeError struct {
+	Err error
+}
+
+func (err *DecodeError) Error() string {
+	return "askgo: unable to decode request: " + err.Err.Error()
+}
+
+// Unwrap returns the JSON error
+func (err *DecodeError) Unwrap() error {
+	return err.Err
+}
+
+// HandlerError wraps the error returned (or the panic raised) by a request handler
+type HandlerError struct {
+	Handler string
+	Err     error
+}
+
+func (err *HandlerError) Error() string {
+	return fmt.Sprintf("askgo: handler %s: %v", err.Handler, err.Err)
+}
+
+// Unwrap returns the error of the handler
+func (err *HandlerError) Unwrap() error {
+	return err.Err
+}
+
+// InterceptorError wraps the error returned (or the panic raised) by a request or response interceptor
+type InterceptorError struct {
+	Interceptor string
+	// Phase is "request" or "response"
+	Phase string
+	Err   error
+}
+
+func (err *InterceptorError) Error() string {
+	return fmt.Sprintf("askgo: %s interceptor %s: %v", err.Phase, err.Interceptor, err.Err)
+}
+
+// Unwrap returns the error of the interceptor
+func (err *InterceptorError) Unwrap() error {
+	return err.Err
+}
+
+// ErrorHandlerError is returned when an error handler fails, it keeps both the error of
+// the error handler and the error it was handling
+type ErrorHandlerError struct {
+	Handler string
+	Err     error
+	// Cause is the error passed to the error handler
+	Cause error
+}
+
+func (err *ErrorHandlerError) Error() string {
+	return fmt.Sprintf("askgo: error handler %s: %v (handling: %v)", err.Handler, err.Err, err.Cause)
+}
+
+// Unwrap returns both errors, so errors.Is and errors.As match either
+func (err *ErrorHandlerError) Unwrap() []error {
+	return []error{err.Err, err.Cause}
+}
+
+// TimeoutError is passed to the error handlers when a request is not processed before
+// the Skill.Timeout, or the deadline of the Lambda invocation
+type TimeoutError struct {
+	Timeout time.Duration
+	// Err is the error of the context, usually context.DeadlineExceeded
+	Err error
+}
+
+func (err *TimeoutError) Error() string {
+	return fmt.Sprintf("askgo: request not processed within %s", err.Timeout.Round(time.Millisecond))
+}
+
+// Unwrap returns the error of the context
+func (err *TimeoutError) Unwrap() error {
+	return err.Err
+}
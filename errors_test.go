@@ -0,0 +1,168 @@
+package askgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/stretchr/testify/require"
+)
+
+var errOutOfStock = errors.New("out of stock")
+
+var discardLogger = askgo.NewSlogLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+type panicInterceptor struct{}
+
+func (panicInterceptor) Process(input askgo.HandlerInput) error {
+	if input.GetRequest().Intent.Name == "PanicInterceptorIntent" {
+		panic("interceptor exploded")
+	}
+	return nil
+}
+
+type recordingErrorHandler struct {
+	errors []error
+	fail   bool
+}
+
+func (handler *recordingErrorHandler) CanHandle(input askgo.HandlerInput, err error) bool {
+	return true
+}
+
+func (handler *recordingErrorHandler) Handle(input askgo.HandlerInput, err error) (*askgo.ResponseEnvelope, error) {
+	handler.errors = append(handler.errors, err)
+	if handler.fail {
+		return nil, errors.New("apology failed")
+	}
+	return input.GetResponse().Speak("Sorry"), nil
+}
+
+func Test_ErrorTypes(t *testing.T) {
+	errorHandler := &recordingErrorHandler{}
+	skill := &askgo.Skill{
+		IgnoreTimestamp:     true,
+		Logger:              discardLogger,
+		RequestInterceptors: []askgo.RequestInterceptor{panicInterceptor{}},
+		Handlers: []askgo.RequestHandler{
+			&askgo.Route{Name: "order", Intents: []string{"OrderIntent"}, Handler: func(askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				return nil, errOutOfStock
+			}},
+			&askgo.Route{Name: "panic", Intents: []string{"PanicIntent"}, Handler: func(askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				var attributes map[string]interface{}
+				attributes["count"] = 1
+				return nil, nil
+			}},
+		},
+		ErrorHandlers: []askgo.ErrorHandler{errorHandler},
+	}
+	process := func(intent string) (interface{}, error) {
+		return skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), intentRequest(intent)))
+	}
+
+	_, err := process("OrderIntent")
+	require.NoError(t, err)
+	var handlerErr *askgo.HandlerError
+	require.True(t, errors.As(errorHandler.errors[0], &handlerErr))
+	require.Equal(t, "order", handlerErr.Handler)
+	require.True(t, errors.Is(errorHandler.errors[0], errOutOfStock))
+
+	_, err = process("PanicIntent")
+	require.NoError(t, err)
+	var panicErr *askgo.PanicError
+	require.True(t, errors.As(errorHandler.errors[1], &panicErr))
+	require.True(t, errors.As(errorHandler.errors[1], &handlerErr))
+	require.Equal(t, "panic", handlerErr.Handler)
+	require.Contains(t, string(panicErr.Stack), "errors_test.go")
+
+	_, err = process("PanicInterceptorIntent")
+	require.NoError(t, err)
+	var interceptorErr *askgo.InterceptorError
+	require.True(t, errors.As(errorHandler.errors[2], &interceptorErr))
+	require.Equal(t, "request", interceptorErr.Phase)
+	require.Equal(t, "askgo: request interceptor askgo_test.panicInterceptor: askgo: panic: interceptor exploded", interceptorErr.Error())
+
+	// The error of the error handler is returned along with the original error
+	errorHandler.fail = true
+	_, err = process("OrderIntent")
+	var errorHandlerErr *askgo.ErrorHandlerError
+	require.True(t, errors.As(err, &errorHandlerErr))
+	require.Equal(t, "apology failed", errorHandlerErr.Err.Error())
+	require.True(t, errors.Is(err, errOutOfStock))
+}
+
+func Test_Invoke(t *testing.T) {
+	skill := &askgo.Skill{
+		ApplicationID: "amzn1.ask.skill.test",
+		Handlers: []askgo.RequestHandler{
+			askgo.RequestRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				return input.GetResponse().Speak("Hello"), nil
+			}, "LaunchRequest"),
+		},
+	}
+
+	_, err := skill.Invoke(context.Background(), []byte(`{"request": []}`))
+	var decodeErr *askgo.DecodeError
+	require.True(t, errors.As(err, &decodeErr))
+
+	_, err = skill.Invoke(context.Background(), []byte(`{"session": {"application": {"applicationId": "other"}}, "request": {"type": "LaunchRequest"}}`))
+	var verificationErr *askgo.VerificationError
+	require.True(t, errors.As(err, &verificationErr))
+
+	skill.IgnoreTimestamp = true
+	output, err := skill.Invoke(context.Background(), []byte(`{"session": {"application": {"applicationId": "amzn1.ask.skill.test"}}, "request": {"type": "LaunchRequest"}}`))
+	require.NoError(t, err)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(output, &response))
+	require.Equal(t, "<speak>Hello</speak>", response["response"].(map[string]interface{})["outputSpeech"].(map[string]interface{})["ssml"])
+}
+
+type rejectionHandler struct {
+	target interface{}
+	speech string
+}
+
+func (handler rejectionHandler) CanHandle(input askgo.HandlerInput, err error) bool {
+	return errors.As(err, handler.target)
+}
+
+func (handler rejectionHandler) Handle(input askgo.HandlerInput, err error) (*askgo.ResponseEnvelope, error) {
+	return input.GetResponse().Speak(handler.speech), nil
+}
+
+func Test_RejectedErrorHandlers(t *testing.T) {
+	var decodeErr *askgo.DecodeError
+	var verificationErr *askgo.VerificationError
+	skill := &askgo.Skill{
+		ApplicationID: "amzn1.ask.skill.test",
+		Logger:        discardLogger,
+		Handlers: []askgo.RequestHandler{
+			askgo.RequestRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				return input.GetResponse().Speak("Hello"), nil
+			}, "LaunchRequest"),
+		},
+		ErrorHandlers: []askgo.ErrorHandler{
+			rejectionHandler{&decodeErr, "Undecodable"},
+			rejectionHandler{&verificationErr, "Unverified"},
+		},
+	}
+	speech := func(output []byte) string {
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(output, &response))
+		return response["response"].(map[string]interface{})["outputSpeech"].(map[string]interface{})["ssml"].(string)
+	}
+
+	output, err := skill.Invoke(context.Background(), []byte(`{"request": []}`))
+	require.NoError(t, err)
+	require.Equal(t, "<speak>Undecodable</speak>", speech(output))
+	require.Contains(t, decodeErr.Error(), "unable to decode request")
+
+	output, err = skill.Invoke(context.Background(), []byte(`{"session": {"application": {"applicationId": "other"}}, "request": {"type": "LaunchRequest"}}`))
+	require.NoError(t, err)
+	require.Equal(t, "<speak>Unverified</speak>", speech(output))
+	require.Contains(t, verificationErr.Error(), "does not match")
+}
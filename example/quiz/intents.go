@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/fatih/structs"
@@ -24,7 +23,7 @@ type unpackAttributes struct{}
 func (h *unpackAttributes) Process(input askgo.HandlerInput) error {
 	attributes := getAttributes(input)
 
-	log.Printf("Got Attributes")
+	input.GetLogger().Debug("Got attributes")
 
 	input.SetContext(context.WithValue(input.GetContext(), &attributeContext, attributes))
 
@@ -39,7 +38,7 @@ func (h *saveAttributes) Process(input askgo.HandlerInput, envelope *askgo.Respo
 		attributes, ok := input.GetContext().Value(&attributeContext).(*Attributes)
 
 		if !ok {
-			log.Printf("Error: Attributes not correct type")
+			input.GetLogger().Error("Attributes not correct type")
 		} else {
 			envelope.SessionAttributes = structs.Map(attributes)
 		}
@@ -59,7 +58,7 @@ func (h *errorHandler) Handle(input askgo.HandlerInput) (*askgo.ResponseEnvelope
 	builder := input.GetResponse().WithShouldEndSession(false)
 	attributes := input.GetContext().Value(&attributeContext).(*Attributes)
 
-	log.Printf("ErrorHandler requestId=%s, sessionId=%s", request.RequestID, attributes.sessionID)
+	input.GetLogger().Info("ErrorHandler", askgo.LogKeyRequestID, request.RequestID, askgo.LogKeySessionID, attributes.sessionID)
 
 	return builder.Speak(helpMessage).Reprompt(helpMessage), nil
 }
@@ -76,7 +75,7 @@ func (h *helpHandler) Handle(input askgo.HandlerInput) (*askgo.ResponseEnvelope,
 	builder := input.GetResponse().WithShouldEndSession(false)
 	attributes := input.GetContext().Value(&attributeContext).(*Attributes)
 
-	log.Printf("HelpHandler requestId=%s, sessionId=%s", request.RequestID, attributes.sessionID)
+	input.GetLogger().Info("HelpHandler", askgo.LogKeyRequestID, request.RequestID, askgo.LogKeySessionID, attributes.sessionID)
 
 	return builder.Speak(helpMessage).Reprompt(helpMessage), nil
 }
@@ -95,7 +94,7 @@ func (h *exitHandler) Handle(input askgo.HandlerInput) (*askgo.ResponseEnvelope,
 	builder := input.GetResponse().WithShouldEndSession(true)
 	attributes := input.GetContext().Value(&attributeContext).(*Attributes)
 
-	log.Printf("ExitHandler requestId=%s, sessionId=%s", request.RequestID, attributes.sessionID)
+	input.GetLogger().Info("ExitHandler", askgo.LogKeyRequestID, request.RequestID, askgo.LogKeySessionID, attributes.sessionID)
 
 	return builder.Speak(exitSkillMessage), nil
 }
@@ -110,7 +109,7 @@ func (h *sessionEndHandler) Handle(input askgo.HandlerInput) (*askgo.ResponseEnv
 	request := input.GetRequest()
 	attributes := input.GetContext().Value(&attributeContext).(*Attributes)
 
-	log.Printf("SessionEnd requestId=%s, sessionId=%s", request.RequestID, attributes.sessionID)
+	input.GetLogger().Info("SessionEnd", askgo.LogKeyRequestID, request.RequestID, askgo.LogKeySessionID, attributes.sessionID)
 
 	return input.GetResponse().WithShouldEndSession(true), nil
 }
@@ -126,7 +125,7 @@ func (h *launchHandler) Handle(input askgo.HandlerInput) (*askgo.ResponseEnvelop
 	response := input.GetResponse().WithShouldEndSession(false)
 	attributes := input.GetContext().Value(&attributeContext).(*Attributes)
 
-	log.Printf("LaunchRequest requestId=%s, sessionId=%s", request.RequestID, attributes.sessionID)
+	input.GetLogger().Info("LaunchRequest", askgo.LogKeyRequestID, request.RequestID, askgo.LogKeySessionID, attributes.sessionID)
 
 	return response.Speak(welcomeMessage).Reprompt(helpMessage), nil
 }
@@ -143,7 +142,7 @@ func (h *repeatHandler) Handle(input askgo.HandlerInput) (*askgo.ResponseEnvelop
 	builder := input.GetResponse().WithShouldEndSession(false)
 	attributes := input.GetContext().Value(&attributeContext).(*Attributes)
 
-	log.Printf("RepeatHandler requestId=%s, sessionId=%s", request.RequestID, attributes.sessionID)
+	input.GetLogger().Info("RepeatHandler", askgo.LogKeyRequestID, request.RequestID, askgo.LogKeySessionID, attributes.sessionID)
 
 	question := getQuestion(attributes)
 
@@ -163,7 +162,7 @@ func (h *quizHandler) Handle(input askgo.HandlerInput) (*askgo.ResponseEnvelope,
 	response := input.GetResponse().WithShouldEndSession(false)
 	attributes := input.GetContext().Value(&attributeContext).(*Attributes)
 
-	log.Printf("QuizHandler requestId=%s, sessionId=%s", request.RequestID, attributes.sessionID)
+	input.GetLogger().Info("QuizHandler", askgo.LogKeyRequestID, request.RequestID, askgo.LogKeySessionID, attributes.sessionID)
 
 	attributes.State = QUIZ
 	attributes.Counter = 0
@@ -226,7 +225,7 @@ func (h *definitionHandler) Handle(input askgo.HandlerInput) (*askgo.ResponseEnv
 	response := input.GetResponse().WithShouldEndSession(false)
 	attributes := input.GetContext().Value(&attributeContext).(*Attributes)
 
-	log.Printf("DefinitionHandler requestId=%s, sessionId=%s", request.RequestID, attributes.sessionID)
+	input.GetLogger().Info("DefinitionHandler", askgo.LogKeyRequestID, request.RequestID, askgo.LogKeySessionID, attributes.sessionID)
 
 	overlap := make(map[string]int)
 
@@ -313,7 +312,7 @@ func (h *quizAnswerHandler) Handle(input askgo.HandlerInput) (*askgo.ResponseEnv
 	response := input.GetResponse().WithShouldEndSession(false)
 	attributes := input.GetContext().Value(&attributeContext).(*Attributes)
 
-	log.Printf("QuizAnswerHandler requestId=%s, sessionId=%s", request.RequestID, attributes.sessionID)
+	input.GetLogger().Info("QuizAnswerHandler", askgo.LogKeyRequestID, request.RequestID, askgo.LogKeySessionID, attributes.sessionID)
 
 	var isCorrect bool
 
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"log"
 	"math/rand"
 	"regexp"
 	"strings"
@@ -259,9 +258,9 @@ func getAttributes(input askgo.HandlerInput) *Attributes {
 
 	if session.Attributes != nil {
 		mapstructure.Decode(session.Attributes, attributes)
-		log.Printf("Attributes = %+v", attributes)
+		input.GetLogger().Debug("Decoded attributes", "attributes", attributes)
 	} else {
-		log.Printf("Attributes = DEFAULT")
+		input.GetLogger().Debug("Default attributes")
 	}
 
 	attributes.sessionID = session.SessionID
@@ -0,0 +1,280 @@
+// Package i18n provides message catalogs keyed on the request locale.
+//
+// Messages are loaded per locale from JSON, YAML or PO files into a Bundle, a Localizer
+// is then created for the locale of the request which resolves keys falling back from
+// the specific locale to the language (e.g. en-GB to en) and finally to the default locale
+// of the bundle.
+//
+// A message value in a JSON or YAML catalog may be
+//
+//	"WELCOME": "Welcome to {0}!"                          a simple string
+//	"CORRECT": ["Booya", "Bam", "Bingo"]                  variants, one is picked at random
+//	"SCORE": {"one": "{0} point", "other": "{0} points"}  plural forms by CLDR category
+//
+// Parameters are interpolated by position ({0}, {1}...) or by name when an Args value
+// is passed ({name}).  The first integer argument selects the plural form.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Args are named parameters for a message (e.g. T("WELCOME", i18n.Args{"name": "Bob"}))
+type Args map[string]interface{}
+
+// Message is a single entry in a catalog
+type Message struct {
+	// Variants of the message, one is picked at random when localized
+	Variants []string
+	// Plurals are the variants for each plural category ("zero", "one", "two", "few", "many", "other")
+	Plurals map[string][]string
+}
+
+// Catalog is the set of messages for a single locale
+type Catalog map[string]Message
+
+// Bundle holds the catalogs for all of the locales of a skill
+type Bundle struct {
+	// DefaultLocale is consulted when a message cannot be found in the requested locale
+	DefaultLocale string
+	// Intn is used to pick a random variant, defaults to math/rand
+	Intn func(n int) int
+
+	mutex    sync.RWMutex
+	catalogs map[string]Catalog
+}
+
+var random = rand.New(rand.NewSource(time.Now().UnixNano()))
+var randomMutex sync.Mutex
+
+func defaultIntn(n int) int {
+	randomMutex.Lock()
+	defer randomMutex.Unlock()
+	return random.Intn(n)
+}
+
+// NewBundle creates an empty bundle with the given default locale
+func NewBundle(defaultLocale string) *Bundle {
+	return &Bundle{DefaultLocale: defaultLocale}
+}
+
+// AddCatalog merges the messages into the catalog for the locale
+func (bundle *Bundle) AddCatalog(locale string, catalog Catalog) {
+	bundle.mutex.Lock()
+	defer bundle.mutex.Unlock()
+
+	if bundle.catalogs == nil {
+		bundle.catalogs = make(map[string]Catalog)
+	}
+	key := normalizeLocale(locale)
+	existing, found := bundle.catalogs[key]
+	if !found {
+		existing = make(Catalog)
+		bundle.catalogs[key] = existing
+	}
+	for k, v := range catalog {
+		existing[k] = v
+	}
+}
+
+// Locales returns the locales that have catalogs
+func (bundle *Bundle) Locales() []string {
+	bundle.mutex.RLock()
+	defer bundle.mutex.RUnlock()
+
+	locales := make([]string, 0, len(bundle.catalogs))
+	for locale := range bundle.catalogs {
+		locales = append(locales, locale)
+	}
+	return locales
+}
+
+func (bundle *Bundle) lookup(locales []string, key string) (Message, string, bool) {
+	bundle.mutex.RLock()
+	defer bundle.mutex.RUnlock()
+
+	for _, locale := range locales {
+		if msg, found := bundle.catalogs[locale][key]; found {
+			return msg, locale, true
+		}
+	}
+	return Message{}, "", false
+}
+
+func (bundle *Bundle) intn(n int) int {
+	if bundle.Intn != nil {
+		return bundle.Intn(n)
+	}
+	return defaultIntn(n)
+}
+
+// Localizer returns a localizer for the given locale
+func (bundle *Bundle) Localizer(locale string) *Localizer {
+	chain := FallbackChain(locale)
+	if bundle.DefaultLocale != "" {
+		for _, fallback := range FallbackChain(bundle.DefaultLocale) {
+			if !contains(chain, fallback) {
+				chain = append(chain, fallback)
+			}
+		}
+	}
+
+	return &Localizer{bundle: bundle, locale: locale, chain: chain}
+}
+
+// Localizer resolves messages for a single locale
+type Localizer struct {
+	bundle *Bundle
+	locale string
+	chain  []string
+}
+
+// Locale is the locale the localizer was created for
+func (localizer *Localizer) Locale() string {
+	return localizer.locale
+}
+
+// Has reports whether a message exists for the key in the locale or one of its fallbacks
+func (localizer *Localizer) Has(key string) bool {
+	_, _, found := localizer.bundle.lookup(localizer.chain, key)
+	return found
+}
+
+// T returns the localized message for the key, interpolating the arguments.  If the message
+// has more than one variant one is chosen at random.  If the key is not found the key
+// itself is returned.
+func (localizer *Localizer) T(key string, args ...interface{}) string {
+	if localizer == nil {
+		return Format(key, args...)
+	}
+
+	msg, locale, found := localizer.bundle.lookup(localizer.chain, key)
+	if !found {
+		return Format(key, args...)
+	}
+
+	variants := msg.Variants
+	if len(msg.Plurals) != 0 {
+		count, _ := firstCount(args)
+		variants = msg.Plurals[PluralCategory(locale, count)]
+		if len(variants) == 0 {
+			variants = msg.Plurals["other"]
+		}
+	}
+
+	switch len(variants) {
+	case 0:
+		return Format(key, args...)
+	case 1:
+		return Format(variants[0], args...)
+	}
+
+	return Format(variants[localizer.bundle.intn(len(variants))], args...)
+}
+
+// Format interpolates the arguments into the message, {0}, {1}... are replaced
+// by positional arguments and {name} by the entries of any Args arguments.
+// Args do not take a position, T(key, Args{...}, 5) replaces {0} by 5.
+func Format(message string, args ...interface{}) string {
+	if len(args) == 0 || !strings.Contains(message, "{") {
+		return message
+	}
+
+	pairs := make([]string, 0, 2*len(args))
+	position := 0
+	for _, arg := range args {
+		if named, ok := arg.(Args); ok {
+			for k, v := range named {
+				pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+			}
+			continue
+		}
+		pairs = append(pairs, fmt.Sprintf("{%d}", position), fmt.Sprint(arg))
+		position++
+	}
+
+	return strings.NewReplacer(pairs...).Replace(message)
+}
+
+func firstCount(args []interface{}) (int, bool) {
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case int:
+			return v, true
+		case int8:
+			return int(v), true
+		case int16:
+			return int(v), true
+		case int32:
+			return int(v), true
+		case int64:
+			return int(v), true
+		case uint:
+			return int(v), true
+		case uint8:
+			return int(v), true
+		case uint16:
+			return int(v), true
+		case uint32:
+			return int(v), true
+		case uint64:
+			return int(v), true
+		}
+	}
+	return 0, false
+}
+
+// FallbackChain returns the locales to search for a locale, e.g. en-GB returns [en-GB, en]
+func FallbackChain(locale string) []string {
+	locale = normalizeLocale(locale)
+	if locale == "" {
+		return nil
+	}
+
+	chain := []string{locale}
+	if idx := strings.Index(locale, "-"); idx > 0 {
+		chain = append(chain, locale[:idx])
+	}
+	return chain
+}
+
+// normalizeLocale accepts en_us, en-us or en-US and returns en-US
+func normalizeLocale(locale string) string {
+	locale = strings.Replace(strings.TrimSpace(locale), "_", "-", -1)
+	parts := strings.SplitN(locale, "-", 2)
+	if len(parts) == 1 {
+		return strings.ToLower(parts[0])
+	}
+	return strings.ToLower(parts[0]) + "-" + strings.ToUpper(parts[1])
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// The key for storing the localizer on a context
+type localizerKey struct{}
+
+// WithLocalizer returns a context carrying the localizer
+func WithLocalizer(ctx context.Context, localizer *Localizer) context.Context {
+	return context.WithValue(ctx, localizerKey{}, localizer)
+}
+
+// FromContext returns the localizer carried by the context, or nil
+func FromContext(ctx context.Context) *Localizer {
+	if ctx == nil {
+		return nil
+	}
+	localizer, _ := ctx.Value(localizerKey{}).(*Localizer)
+	return localizer
+}
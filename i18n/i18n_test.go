@@ -0,0 +1,92 @@
+package i18n_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koblas/askgo/i18n"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Fallback(t *testing.T) {
+	bundle := i18n.NewBundle("en-US")
+	require.NoError(t, bundle.LoadJSON("en", strings.NewReader(`{
+		"WELCOME": "Welcome to {0}!",
+		"COLOR": "color"
+	}`)))
+	require.NoError(t, bundle.LoadYAML("en-GB", strings.NewReader(`COLOR: colour`)))
+	require.NoError(t, bundle.LoadJSON("en-US", strings.NewReader(`{"ONLY_US": "howdy"}`)))
+
+	localizer := bundle.Localizer("en-GB")
+	require.Equal(t, "colour", localizer.T("COLOR"))
+	require.Equal(t, "Welcome to the quiz!", localizer.T("WELCOME", "the quiz"))
+	require.Equal(t, "howdy", localizer.T("ONLY_US"))
+	require.Equal(t, "MISSING", localizer.T("MISSING"))
+
+	require.Equal(t, "color", bundle.Localizer("en-AU").T("COLOR"))
+}
+
+func Test_PluralAndVariants(t *testing.T) {
+	bundle := i18n.NewBundle("en")
+	bundle.Intn = func(n int) int { return n - 1 }
+
+	require.NoError(t, bundle.LoadYAML("en", strings.NewReader(`
+SCORE:
+  one: "{0} point for {name}"
+  other: "{0} points for {name}"
+CORRECT:
+  - Booya
+  - Bam
+`)))
+
+	localizer := bundle.Localizer("en-US")
+	require.Equal(t, "1 point for Bob", localizer.T("SCORE", 1, i18n.Args{"name": "Bob"}))
+	require.Equal(t, "3 points for Bob", localizer.T("SCORE", 3, i18n.Args{"name": "Bob"}))
+	// Args don't take a position
+	require.Equal(t, "5 points for Bob", localizer.T("SCORE", i18n.Args{"name": "Bob"}, 5))
+	require.Equal(t, "Bam", localizer.T("CORRECT"))
+}
+
+func Test_LoadPO(t *testing.T) {
+	bundle := i18n.NewBundle("fr")
+
+	require.NoError(t, bundle.LoadPO("fr", strings.NewReader(`
+# Header
+msgid ""
+msgstr ""
+"Language: fr\n"
+
+msgid "HELLO"
+msgstr "Bonjour "
+"{0}"
+
+msgid "SCORE"
+msgid_plural "SCORES"
+msgstr[0] "{0} point"
+msgstr[1] "{0} points"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Ouvrir"
+
+msgctxt "door"
+msgid "Open"
+msgstr "Ouverte"
+`)))
+
+	localizer := bundle.Localizer("fr-FR")
+	require.Equal(t, "Bonjour Marie", localizer.T("HELLO", "Marie"))
+	require.Equal(t, "0 point", localizer.T("SCORE", 0))
+	require.Equal(t, "2 points", localizer.T("SCORE", 2))
+	require.Equal(t, "Ouvrir", localizer.T(i18n.ContextKey("menu", "Open")))
+	require.Equal(t, "Ouverte", localizer.T(i18n.ContextKey("door", "Open")))
+
+	err := bundle.LoadPO("fr", strings.NewReader(`
+msgid "HELLO"
+msgstr "Bonjour"
+
+msgid "HELLO"
+msgstr "Salut"
+`))
+	require.ErrorContains(t, err, "duplicate")
+}
@@ -0,0 +1,321 @@
+package i18n
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// LoadJSON reads a JSON catalog for the locale
+func (bundle *Bundle) LoadJSON(locale string, reader io.Reader) error {
+	raw := make(map[string]interface{})
+	if err := json.NewDecoder(reader).Decode(&raw); err != nil {
+		return fmt.Errorf("i18n: unable to parse JSON catalog for %s: %v", locale, err)
+	}
+	return bundle.addRaw(locale, raw)
+}
+
+// LoadYAML reads a YAML catalog for the locale
+func (bundle *Bundle) LoadYAML(locale string, reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	raw := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("i18n: unable to parse YAML catalog for %s: %v", locale, err)
+	}
+	return bundle.addRaw(locale, raw)
+}
+
+// LoadPO reads a gettext PO catalog for the locale, the msgid is used as the key, or
+// ContextKey(msgctxt, msgid) for the entries with a context.
+// Plural entries (msgid_plural) map msgstr[n] onto the plural categories of the language.
+func (bundle *Bundle) LoadPO(locale string, reader io.Reader) error {
+	entries, err := parsePO(reader)
+	if err != nil {
+		return fmt.Errorf("i18n: unable to parse PO catalog for %s: %v", locale, err)
+	}
+
+	categories := pluralCategories(locale)
+	catalog := make(Catalog)
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		// The header entry
+		if entry.id == "" {
+			continue
+		}
+		key := entry.id
+		if entry.context != nil {
+			key = ContextKey(*entry.context, entry.id)
+		}
+		if seen[key] {
+			return fmt.Errorf("i18n: duplicate message %q in PO catalog for %s", key, locale)
+		}
+		seen[key] = true
+
+		if entry.plural == "" {
+			if text := entry.str[0]; text != "" {
+				catalog[key] = Message{Variants: []string{text}}
+			}
+			continue
+		}
+
+		plurals := make(map[string][]string)
+		for idx, text := range entry.str {
+			if idx < len(categories) && text != "" {
+				plurals[categories[idx]] = []string{text}
+			}
+		}
+		if len(plurals) != 0 {
+			catalog[key] = Message{Plurals: plurals}
+		}
+	}
+
+	bundle.AddCatalog(locale, catalog)
+	return nil
+}
+
+// ContextKey is the key of a PO message with a msgctxt, the context and msgid joined by
+// the EOT character as gettext does (e.g. T(i18n.ContextKey("menu", "Open")))
+func ContextKey(context, id string) string {
+	return context + "\x04" + id
+}
+
+// LoadFile loads a catalog, the locale is taken from the file name (e.g. en-US.json)
+// and the format from the extension (.json, .yaml, .yml or .po)
+func (bundle *Bundle) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	ext := filepath.Ext(path)
+	locale := strings.TrimSuffix(filepath.Base(path), ext)
+
+	switch strings.ToLower(ext) {
+	case ".json":
+		return bundle.LoadJSON(locale, file)
+	case ".yaml", ".yml":
+		return bundle.LoadYAML(locale, file)
+	case ".po":
+		return bundle.LoadPO(locale, file)
+	}
+
+	return fmt.Errorf("i18n: unknown catalog format %s", path)
+}
+
+// LoadDir loads every catalog in the directory
+func (bundle *Bundle) LoadDir(dir string) error {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(file.Name())) {
+		case ".json", ".yaml", ".yml", ".po":
+			if err := bundle.LoadFile(filepath.Join(dir, file.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (bundle *Bundle) addRaw(locale string, raw map[string]interface{}) error {
+	catalog := make(Catalog)
+	for key, value := range raw {
+		msg, err := toMessage(value)
+		if err != nil {
+			return fmt.Errorf("i18n: message %s for %s: %v", key, locale, err)
+		}
+		catalog[key] = msg
+	}
+
+	bundle.AddCatalog(locale, catalog)
+	return nil
+}
+
+func toMessage(value interface{}) (Message, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return toPlural(func(fn func(string, interface{}) error) error {
+			for k, item := range v {
+				if err := fn(k, item); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case map[interface{}]interface{}:
+		return toPlural(func(fn func(string, interface{}) error) error {
+			for k, item := range v {
+				if err := fn(fmt.Sprint(k), item); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	variants, err := toVariants(value)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Variants: variants}, nil
+}
+
+func toPlural(each func(fn func(string, interface{}) error) error) (Message, error) {
+	plurals := make(map[string][]string)
+
+	err := each(func(category string, value interface{}) error {
+		switch category {
+		case "zero", "one", "two", "few", "many", "other":
+		default:
+			return fmt.Errorf("unknown plural category %q", category)
+		}
+		variants, err := toVariants(value)
+		if err != nil {
+			return err
+		}
+		plurals[category] = variants
+		return nil
+	})
+
+	return Message{Plurals: plurals}, err
+}
+
+func toVariants(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		variants := make([]string, 0, len(v))
+		for _, item := range v {
+			text, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("variants must be strings")
+			}
+			variants = append(variants, text)
+		}
+		return variants, nil
+	}
+
+	return nil, fmt.Errorf("unsupported message type %T", value)
+}
+
+type poEntry struct {
+	// context is the msgctxt of the entry, nil when it has none
+	context *string
+	id      string
+	plural  string
+	str     []string
+}
+
+// parsePO is a minimal parser for the gettext PO format, comments are ignored
+func parsePO(reader io.Reader) ([]poEntry, error) {
+	entries := make([]poEntry, 0)
+	scanner := bufio.NewScanner(reader)
+
+	var current *poEntry
+	// The string currently being appended to by continuation lines
+	var target *string
+
+	flush := func() {
+		if current != nil {
+			entries = append(entries, *current)
+		}
+		current = nil
+		target = nil
+	}
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case text == "" || strings.HasPrefix(text, "#"):
+			continue
+		case strings.HasPrefix(text, `"`):
+			if target == nil {
+				return nil, fmt.Errorf("line %d: unexpected string", line)
+			}
+			value, err := strconv.Unquote(text)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			*target += value
+			continue
+		}
+
+		keyword, rest := text, ""
+		if idx := strings.IndexAny(text, " \t"); idx > 0 {
+			keyword, rest = text[:idx], strings.TrimSpace(text[idx:])
+		}
+		value, err := strconv.Unquote(rest)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		switch {
+		case keyword == "msgctxt":
+			flush()
+			current = &poEntry{context: &value}
+			target = current.context
+		case keyword == "msgid":
+			if current == nil || current.id != "" || len(current.str) != 0 {
+				flush()
+				current = &poEntry{}
+			}
+			current.id = value
+			target = &current.id
+		case keyword == "msgid_plural" && current != nil:
+			current.plural = value
+			target = &current.plural
+		case keyword == "msgstr" && current != nil:
+			current.str = []string{value}
+			target = &current.str[0]
+		case strings.HasPrefix(keyword, "msgstr[") && current != nil:
+			idx, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(keyword, "msgstr["), "]"))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: bad plural index", line)
+			}
+			for len(current.str) <= idx {
+				current.str = append(current.str, "")
+			}
+			current.str[idx] = value
+			target = &current.str[idx]
+		default:
+			return nil, fmt.Errorf("line %d: unexpected %s", line, keyword)
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Entries without a translation have no msgstr at all
+	result := entries[:0]
+	for _, entry := range entries {
+		if len(entry.str) != 0 {
+			result = append(result, entry)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package i18n
+
+import "strings"
+
+// PluralCategory returns the CLDR plural category ("zero", "one", "two", "few", "many"
+// or "other") of the count for the language of the locale.  Only the cardinal rules
+// for integers of the languages supported by Alexa are implemented, anything else uses
+// the English rule.
+func PluralCategory(locale string, count int) string {
+	if count < 0 {
+		count = -count
+	}
+
+	switch language(locale) {
+	case "ja", "zh", "ko":
+		return "other"
+	case "fr", "pt", "hi":
+		if count == 0 || count == 1 {
+			return "one"
+		}
+		return "other"
+	case "ar":
+		mod := count % 100
+		switch {
+		case count == 0:
+			return "zero"
+		case count == 1:
+			return "one"
+		case count == 2:
+			return "two"
+		case mod >= 3 && mod <= 10:
+			return "few"
+		case mod >= 11:
+			return "many"
+		}
+		return "other"
+	}
+
+	if count == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// pluralCategories are the categories of a language in the order used by
+// the msgstr[n] entries of a PO file.
+func pluralCategories(locale string) []string {
+	switch language(locale) {
+	case "ja", "zh", "ko":
+		return []string{"other"}
+	case "ar":
+		return []string{"zero", "one", "two", "few", "many", "other"}
+	}
+	return []string{"one", "other"}
+}
+
+func language(locale string) string {
+	locale = normalizeLocale(locale)
+	if idx := strings.Index(locale, "-"); idx > 0 {
+		return locale[:idx]
+	}
+	return locale
+}
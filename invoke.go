@@ -0,0 +1,31 @@
+package askgo
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Invoke decodes the request envelope from the payload, processes it and returns the
+// encoded response.  It implements the lambda.Handler interface of aws-lambda-go:
+//
+//	lambda.StartHandler(skill)
+//
+// A payload that cannot be decoded is passed to the error handlers as a DecodeError, with an
+// empty request envelope.
+func (skill *Skill) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	var response interface{}
+	var err error
+
+	envelope := &RequestEnvelope{}
+	if decodeErr := json.Unmarshal(payload, envelope); decodeErr != nil {
+		input := NewDefaultHandler(ctx, &RequestEnvelope{})
+		scope, _ := skill.start(input)
+		response, err = skill.reject(input, scope, &DecodeError{Err: decodeErr})
+	} else {
+		response, err = skill.ProcessRequest(NewDefaultHandler(ctx, envelope))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(response)
+}
@@ -0,0 +1,94 @@
+// Package jsonlogic builds JSON Logic (jsonlogic.com) expressions, as used by the event
+// filters of custom interface event handlers.
+//
+//	filter := jsonlogic.And(
+//		jsonlogic.Equal(jsonlogic.Var("header.namespace"), "Custom.Robot"),
+//		jsonlogic.Equal(jsonlogic.Var("endpoint.endpointId"), endpointID),
+//	)
+//	response.AddCustomInterfaceStartEventHandlerDirective(token, filter, "SEND", 60000, nil)
+package jsonlogic
+
+// Rule is a JSON Logic operation, it marshals to {"operator": [arguments...]}
+type Rule map[string]interface{}
+
+func operation(operator string, args ...interface{}) Rule {
+	if args == nil {
+		args = []interface{}{}
+	}
+	return Rule{operator: args}
+}
+
+// Var is the value at the dotted path of the data (e.g. "payload.speed")
+func Var(path string) Rule {
+	return Rule{"var": path}
+}
+
+// VarDefault is the value at the path of the data, or the default when it is missing
+func VarDefault(path string, value interface{}) Rule {
+	return operation("var", path, value)
+}
+
+// Equal compares the values with ==
+func Equal(a, b interface{}) Rule {
+	return operation("==", a, b)
+}
+
+// StrictEqual compares the values with ===
+func StrictEqual(a, b interface{}) Rule {
+	return operation("===", a, b)
+}
+
+// NotEqual compares the values with !=
+func NotEqual(a, b interface{}) Rule {
+	return operation("!=", a, b)
+}
+
+// Greater compares the values with >
+func Greater(a, b interface{}) Rule {
+	return operation(">", a, b)
+}
+
+// GreaterOrEqual compares the values with >=
+func GreaterOrEqual(a, b interface{}) Rule {
+	return operation(">=", a, b)
+}
+
+// Less compares the values with <
+func Less(a, b interface{}) Rule {
+	return operation("<", a, b)
+}
+
+// LessOrEqual compares the values with <=
+func LessOrEqual(a, b interface{}) Rule {
+	return operation("<=", a, b)
+}
+
+// Between is true when low < value < high
+func Between(low, value, high interface{}) Rule {
+	return operation("<", low, value, high)
+}
+
+// And is true when every rule is true
+func And(rules ...interface{}) Rule {
+	return operation("and", rules...)
+}
+
+// Or is true when any rule is true
+func Or(rules ...interface{}) Rule {
+	return operation("or", rules...)
+}
+
+// Not negates the rule
+func Not(rule interface{}) Rule {
+	return operation("!", rule)
+}
+
+// In is true when the value is in the list, or is a substring of the string
+func In(value interface{}, list interface{}) Rule {
+	return operation("in", value, list)
+}
+
+// If returns then when the condition is true, otherwise otherwise
+func If(condition, then, otherwise interface{}) Rule {
+	return operation("if", condition, then, otherwise)
+}
@@ -0,0 +1,25 @@
+package jsonlogic_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/koblas/askgo/jsonlogic"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Marshal(t *testing.T) {
+	rule := jsonlogic.And(
+		jsonlogic.Equal(jsonlogic.Var("header.namespace"), "Custom.Robot"),
+		jsonlogic.Not(jsonlogic.In(jsonlogic.Var("header.name"), []string{"Idle"})),
+		jsonlogic.Between(0, jsonlogic.VarDefault("payload.speed", 0), 10),
+	)
+
+	data, err := json.Marshal(rule)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"and": [
+		{"==": [{"var": "header.namespace"}, "Custom.Robot"]},
+		{"!": [{"in": [{"var": "header.name"}, ["Idle"]]}]},
+		{"<": [0, {"var": ["payload.speed", 0]}, 10]}
+	]}`, string(data))
+}
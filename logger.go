@@ -0,0 +1,95 @@
+package askgo
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Logger is a leveled structured logger, the keysAndValues are alternating keys and values
+// (e.g. logger.Info("answered", "correct", true))
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+
+	// With returns a logger that adds the keysAndValues to every entry
+	With(keysAndValues ...interface{}) Logger
+}
+
+// Keys of the request fields attached to the entries of the request logger
+const (
+	LogKeyRequestID   = "request_id"
+	LogKeySessionID   = "session_id"
+	LogKeyRequestType = "request_type"
+	LogKeyIntent      = "intent"
+	LogKeyLocale      = "locale"
+)
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger adapts a slog.Logger, nil uses slog.Default()
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogLogger{logger}
+}
+
+func (l *slogLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.logger.Debug(msg, keysAndValues...)
+}
+
+func (l *slogLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.Info(msg, keysAndValues...)
+}
+
+func (l *slogLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.logger.Warn(msg, keysAndValues...)
+}
+
+func (l *slogLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.logger.Error(msg, keysAndValues...)
+}
+
+func (l *slogLogger) With(keysAndValues ...interface{}) Logger {
+	return &slogLogger{l.logger.With(keysAndValues...)}
+}
+
+type loggerKey struct{}
+
+// WithLogger returns a context carrying the logger
+func WithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFromContext returns the logger of the context, or a logger using slog.Default()
+func LoggerFromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(Logger); ok {
+			return logger
+		}
+	}
+	return NewSlogLogger(nil)
+}
+
+// requestLogger attaches the fields identifying the request to the logger
+func requestLogger(logger Logger, envelope RequestEnvelope) Logger {
+	request := envelope.Request
+
+	keysAndValues := []interface{}{
+		LogKeyRequestID, request.RequestID,
+		LogKeyRequestType, request.Type,
+		LogKeyLocale, request.Locale,
+	}
+	if envelope.Session.SessionID != "" {
+		keysAndValues = append(keysAndValues, LogKeySessionID, envelope.Session.SessionID)
+	}
+	if request.Intent.Name != "" {
+		keysAndValues = append(keysAndValues, LogKeyIntent, request.Intent.Name)
+	}
+
+	return logger.With(keysAndValues...)
+}
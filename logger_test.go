@@ -0,0 +1,57 @@
+package askgo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RequestLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		Logger:          askgo.NewSlogLogger(logger),
+		Handlers: []askgo.RequestHandler{
+			askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				input.GetLogger().Info("answered", "correct", true)
+				return input.GetResponse().Speak("Right"), nil
+			}, "AnswerIntent"),
+		},
+	}
+
+	envelope := &askgo.RequestEnvelope{
+		Session: alexa.Session{SessionID: "session-1"},
+		Request: alexa.Request{Type: "IntentRequest", RequestID: "request-1", Locale: "en-GB", Intent: alexa.Intent{Name: "AnswerIntent"}},
+	}
+	_, err := skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), envelope))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+
+	entries := make([]map[string]interface{}, len(lines))
+	for idx, line := range lines {
+		require.NoError(t, json.Unmarshal([]byte(line), &entries[idx]))
+	}
+	require.Equal(t, "DEBUG", entries[0]["level"])
+	require.Equal(t, "Ignoring application verification.", entries[0]["msg"])
+
+	entry := entries[2]
+	require.Equal(t, "INFO", entry["level"])
+	require.Equal(t, "answered", entry["msg"])
+	require.Equal(t, true, entry["correct"])
+	require.Equal(t, "request-1", entry[askgo.LogKeyRequestID])
+	require.Equal(t, "session-1", entry[askgo.LogKeySessionID])
+	require.Equal(t, "IntentRequest", entry[askgo.LogKeyRequestType])
+	require.Equal(t, "AnswerIntent", entry[askgo.LogKeyIntent])
+	require.Equal(t, "en-GB", entry[askgo.LogKeyLocale])
+}
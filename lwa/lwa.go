@@ -0,0 +1,183 @@
+// Package lwa obtains Login with Amazon access tokens for the skill itself, using the client
+// credentials grant, as needed by the out-of-session APIs (proactive events, skill messaging),
+// and resolves account linking access tokens into customer profiles.
+//
+//	client := &lwa.Client{ClientID: os.Getenv("SKILL_CLIENT_ID"), ClientSecret: os.Getenv("SKILL_CLIENT_SECRET")}
+//	token, err := client.Token(ctx, "alexa::proactive_events")
+package lwa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTokenURL is the Login with Amazon token endpoint
+const DefaultTokenURL = "https://api.amazon.com/auth/o2/token"
+
+// expiryMargin is how long before their expiry tokens are renewed, at most half of their lifetime
+const expiryMargin = time.Minute
+
+// Client exchanges the client ID and secret of the skill for access tokens, which are
+// cached by scope until shortly before they expire
+type Client struct {
+	ClientID     string
+	ClientSecret string
+	// TokenURL defaults to DefaultTokenURL
+	TokenURL string
+	// ProfileURL defaults to DefaultProfileURL
+	ProfileURL string
+	// HTTPClient defaults to http.DefaultClient
+	HTTPClient *http.Client
+
+	mutex   sync.Mutex
+	tokens  map[string]token
+	pending map[string]*tokenCall
+}
+
+type token struct {
+	value   string
+	expires time.Time
+}
+
+// tokenCall is a token being fetched, concurrent callers for the scope wait for it
+type tokenCall struct {
+	done  chan struct{}
+	token token
+	err   error
+	// cancelled is set when the context of the caller making the request ended, the other
+	// callers make a request of their own
+	cancelled bool
+}
+
+// Error is returned when Login with Amazon refuses the credentials
+type Error struct {
+	StatusCode  int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("lwa: status %d: %s: %s", err.StatusCode, err.Code, err.Description)
+}
+
+// Token returns an access token for the scope (e.g. "alexa::proactive_events"), a single
+// request is made for the callers asking for the same scope at the same time
+func (client *Client) Token(ctx context.Context, scope string) (string, error) {
+	for {
+		client.mutex.Lock()
+		if cached, found := client.tokens[scope]; found && time.Now().Before(cached.expires) {
+			client.mutex.Unlock()
+			return cached.value, nil
+		}
+		call, found := client.pending[scope]
+		if !found {
+			break
+		}
+		client.mutex.Unlock()
+
+		select {
+		case <-call.done:
+			if !call.cancelled {
+				return call.token.value, call.err
+			}
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	call := &tokenCall{done: make(chan struct{})}
+	if client.pending == nil {
+		client.pending = make(map[string]*tokenCall)
+	}
+	client.pending[scope] = call
+	client.mutex.Unlock()
+
+	call.token, call.err = client.fetch(ctx, scope)
+	call.cancelled = call.err != nil && ctx.Err() != nil
+
+	client.mutex.Lock()
+	delete(client.pending, scope)
+	if call.err == nil {
+		if client.tokens == nil {
+			client.tokens = make(map[string]token)
+		}
+		client.tokens[scope] = call.token
+	}
+	client.mutex.Unlock()
+	close(call.done)
+
+	return call.token.value, call.err
+}
+
+// Invalidate drops the cached token of the scope, e.g. after an API rejected it
+func (client *Client) Invalidate(scope string) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	delete(client.tokens, scope)
+}
+
+func (client *Client) httpClient() *http.Client {
+	if client.HTTPClient != nil {
+		return client.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (client *Client) fetch(ctx context.Context, scope string) (token, error) {
+	tokenURL := client.TokenURL
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {client.ClientID},
+		"client_secret": {client.ClientSecret},
+		"scope":         {scope},
+	}
+
+	req, err := http.NewRequest("POST", tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return token{}, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.httpClient().Do(req)
+	if err != nil {
+		return token{}, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return token{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		lwaErr := &Error{StatusCode: resp.StatusCode}
+		json.Unmarshal(data, lwaErr)
+		return token{}, lwaErr
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return token{}, err
+	}
+
+	lifetime := time.Duration(result.ExpiresIn) * time.Second
+	margin := expiryMargin
+	if margin > lifetime/2 {
+		margin = lifetime / 2
+	}
+	return token{value: result.AccessToken, expires: time.Now().Add(lifetime - margin)}, nil
+}
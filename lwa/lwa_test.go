@@ -0,0 +1,250 @@
+package lwa_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/lwa"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenServer answers the client credentials grant and records the forms it received
+type tokenServer struct {
+	*httptest.Server
+	mutex sync.Mutex
+	forms []url.Values
+	body  string
+}
+
+func newTokenServer(body string) *tokenServer {
+	server := &tokenServer{body: body}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		server.mutex.Lock()
+		server.forms = append(server.forms, r.PostForm)
+		server.mutex.Unlock()
+		if r.PostForm.Get("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "invalid_client", "error_description": "Client authentication failed"}`))
+			return
+		}
+		w.Write([]byte(server.body))
+	}))
+	return server
+}
+
+func (server *tokenServer) requests() []url.Values {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return append([]url.Values(nil), server.forms...)
+}
+
+func Test_Token(t *testing.T) {
+	server := newTokenServer(`{"access_token": "Atc|token", "expires_in": 3600, "token_type": "bearer"}`)
+	defer server.Close()
+	client := &lwa.Client{ClientID: "id", ClientSecret: "secret", TokenURL: server.URL}
+
+	token, err := client.Token(context.Background(), "alexa::proactive_events")
+	require.NoError(t, err)
+	require.Equal(t, "Atc|token", token)
+	_, err = client.Token(context.Background(), "alexa::proactive_events")
+	require.NoError(t, err)
+
+	requests := server.requests()
+	require.Len(t, requests, 1)
+	require.Equal(t, "client_credentials", requests[0].Get("grant_type"))
+	require.Equal(t, "id", requests[0].Get("client_id"))
+	require.Equal(t, "alexa::proactive_events", requests[0].Get("scope"))
+
+	// Tokens are cached by scope, and fetched again once invalidated
+	_, err = client.Token(context.Background(), "alexa:skill_messaging")
+	require.NoError(t, err)
+	client.Invalidate("alexa::proactive_events")
+	_, err = client.Token(context.Background(), "alexa::proactive_events")
+	require.NoError(t, err)
+	require.Len(t, server.requests(), 3)
+}
+
+func Test_TokenError(t *testing.T) {
+	server := newTokenServer(`{}`)
+	defer server.Close()
+	client := &lwa.Client{ClientID: "id", ClientSecret: "wrong", TokenURL: server.URL}
+
+	_, err := client.Token(context.Background(), "alexa::proactive_events")
+	var lwaErr *lwa.Error
+	require.True(t, errors.As(err, &lwaErr))
+	require.Equal(t, http.StatusUnauthorized, lwaErr.StatusCode)
+	require.Equal(t, "invalid_client", lwaErr.Code)
+	// A wrong client secret is not an unlinked account
+	require.False(t, errors.Is(err, askgo.ErrAccountNotLinked))
+}
+
+func Test_Profile(t *testing.T) {
+	var mutex sync.Mutex
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		mutex.Unlock()
+		switch r.Header.Get("Authorization") {
+		case "Bearer Atza|valid":
+			w.Write([]byte(`{"user_id": "amzn1.account.1", "name": "Ada", "email": "ada@example.com"}`))
+		case "Bearer Atza|expired":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "invalid_token", "error_description": "The access token has expired"}`))
+		case "Bearer Atza|revoked":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	client := &lwa.Client{ProfileURL: server.URL}
+
+	profile, err := client.Profile(context.Background(), "Atza|valid")
+	require.NoError(t, err)
+	require.Equal(t, &lwa.Profile{UserID: "amzn1.account.1", Name: "Ada", Email: "ada@example.com"}, profile)
+
+	for _, token := range []string{"Atza|expired", "Atza|revoked"} {
+		_, err = client.Profile(context.Background(), token)
+		require.True(t, errors.Is(err, askgo.ErrAccountNotLinked), token)
+		var lwaErr *lwa.Error
+		require.True(t, errors.As(err, &lwaErr), token)
+	}
+
+	_, err = client.Profile(context.Background(), "Atza|server-error")
+	var profileErr *lwa.ProfileError
+	require.True(t, errors.As(err, &profileErr))
+	require.Equal(t, http.StatusInternalServerError, profileErr.Err.StatusCode)
+	require.False(t, errors.Is(err, askgo.ErrAccountNotLinked))
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.Equal(t, "Bearer Atza|valid", authorizations[0])
+}
+
+func Test_TokenShortLifetime(t *testing.T) {
+	// A lifetime under the renewal margin is still cached for half of it
+	server := newTokenServer(`{"access_token": "Atc|short", "expires_in": 30}`)
+	defer server.Close()
+	client := &lwa.Client{ClientID: "id", ClientSecret: "secret", TokenURL: server.URL}
+
+	for i := 0; i < 3; i++ {
+		token, err := client.Token(context.Background(), "alexa::proactive_events")
+		require.NoError(t, err)
+		require.Equal(t, "Atc|short", token)
+	}
+	require.Len(t, server.requests(), 1)
+}
+
+func Test_TokenConcurrent(t *testing.T) {
+	release := make(chan struct{})
+	var mutex sync.Mutex
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		fetches++
+		mutex.Unlock()
+		<-release
+		w.Write([]byte(`{"access_token": "Atc|token", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+	client := &lwa.Client{ClientID: "id", ClientSecret: "secret", TokenURL: server.URL}
+
+	results := make(chan string, 5)
+	for i := 0; i < cap(results); i++ {
+		go func() {
+			token, _ := client.Token(context.Background(), "alexa::proactive_events")
+			results <- token
+		}()
+	}
+
+	// Other scopes are not blocked by the pending fetch
+	other := make(chan error, 1)
+	go func() {
+		_, err := client.Token(context.Background(), "alexa:skill_messaging")
+		other <- err
+	}()
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return fetches == 2
+	}, time.Second, time.Millisecond)
+
+	close(release)
+	for i := 0; i < cap(results); i++ {
+		require.Equal(t, "Atc|token", <-results)
+	}
+	require.NoError(t, <-other)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.Equal(t, 2, fetches)
+}
+
+func Test_TokenLeaderCancelled(t *testing.T) {
+	var mutex sync.Mutex
+	fetches := 0
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		fetches++
+		first := fetches == 1
+		mutex.Unlock()
+		if first {
+			// Held until the caller gave up
+			<-release
+			return
+		}
+		w.Write([]byte(`{"access_token": "Atc|token", "expires_in": 3600}`))
+	}))
+	defer server.Close()
+	defer close(release)
+	client := &lwa.Client{ClientID: "id", ClientSecret: "secret", TokenURL: server.URL}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error, 1)
+	go func() {
+		_, err := client.Token(ctx, "alexa::proactive_events")
+		leader <- err
+	}()
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return fetches == 1
+	}, time.Second, time.Millisecond)
+
+	type result struct {
+		token string
+		err   error
+	}
+	results := make(chan result, 3)
+	for i := 0; i < cap(results); i++ {
+		go func() {
+			token, err := client.Token(context.Background(), "alexa::proactive_events")
+			results <- result{token, err}
+		}()
+	}
+	// Give the other callers time to wait on the pending request
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	require.True(t, errors.Is(<-leader, context.Canceled))
+	for i := 0; i < cap(results); i++ {
+		r := <-results
+		require.NoError(t, r.err)
+		require.Equal(t, "Atc|token", r.token)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.Equal(t, 2, fetches, "the waiting callers share a single new request")
+}
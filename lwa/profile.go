@@ -0,0 +1,89 @@
+package lwa
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/koblas/askgo"
+)
+
+// DefaultProfileURL is the Login with Amazon customer profile endpoint
+const DefaultProfileURL = "https://api.amazon.com/user/profile"
+
+// Profile is the Amazon customer profile of an account linking access token, the fields
+// other than UserID depend on the scopes granted by the user
+type Profile struct {
+	UserID     string `json:"user_id"`
+	Name       string `json:"name,omitempty"`
+	Email      string `json:"email,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+}
+
+// ProfileError is returned by Profile when Login with Amazon refuses the access token, a
+// rejected token matches askgo.ErrAccountNotLinked
+type ProfileError struct {
+	Err *Error
+}
+
+func (err *ProfileError) Error() string {
+	return err.Err.Error()
+}
+
+// Unwrap returns the Login with Amazon error
+func (err *ProfileError) Unwrap() error {
+	return err.Err
+}
+
+// Is matches askgo.ErrAccountNotLinked for a rejected access token
+func (err *ProfileError) Is(target error) bool {
+	return target == askgo.ErrAccountNotLinked && (err.Err.StatusCode == http.StatusUnauthorized || err.Err.Code == "invalid_token")
+}
+
+// Profile returns the customer profile of the user of an account linking access token
+func (client *Client) Profile(ctx context.Context, accessToken string) (*Profile, error) {
+	profileURL := client.ProfileURL
+	if profileURL == "" {
+		profileURL = DefaultProfileURL
+	}
+
+	req, err := http.NewRequest("GET", profileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		lwaErr := &Error{StatusCode: resp.StatusCode}
+		json.Unmarshal(data, lwaErr)
+		return nil, &ProfileError{Err: lwaErr}
+	}
+
+	profile := &Profile{}
+	if err := json.Unmarshal(data, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Resolve is Profile with the signature of askgo.AccountLinker.Resolve, the identity is the *Profile
+func (client *Client) Resolve(ctx context.Context, accessToken string) (interface{}, error) {
+	profile, err := client.Profile(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
@@ -0,0 +1,71 @@
+// Package messaging sends data to a skill on behalf of a user with the Skill Messaging API,
+// the skill receives it as a Messaging.MessageReceived request (without a session).
+//
+//	client := &messaging.Client{LWA: &lwa.Client{ClientID: id, ClientSecret: secret}}
+//	err := client.Send(ctx, userID, map[string]interface{}{"quiz": "weekly"}, time.Hour)
+//
+//	skill.Handlers = append(skill.Handlers, askgo.RequestRoute(onMessage, messaging.MessageReceived))
+package messaging
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/lwa"
+	"github.com/koblas/askgo/proactive"
+)
+
+// Scope is the Login with Amazon scope of the Skill Messaging API
+const Scope = "alexa:skill_messaging"
+
+// MessageReceived is the type of the requests carrying the messages
+const MessageReceived = "Messaging.MessageReceived"
+
+// DefaultExpiry is how long a message is kept when it cannot be delivered
+const DefaultExpiry = time.Hour
+
+// Client sends messages to the Skill Messaging API
+type Client struct {
+	// LWA provides the access tokens of the skill
+	LWA *lwa.Client
+	// Endpoint is the API endpoint of the region (e.g. proactive.Europe), defaults to proactive.NorthAmerica
+	Endpoint string
+	// HTTPClient defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+type message struct {
+	Data                interface{} `json:"data"`
+	ExpiresAfterSeconds int         `json:"expiresAfterSeconds"`
+}
+
+// Send delivers the data to the skill for the user, it is dropped when the skill cannot be
+// reached before expiresAfter (DefaultExpiry when zero)
+func (client *Client) Send(ctx context.Context, userID string, data interface{}, expiresAfter time.Duration) error {
+	token, err := client.LWA.Token(ctx, Scope)
+	if err != nil {
+		return err
+	}
+
+	endpoint := client.Endpoint
+	if endpoint == "" {
+		endpoint = proactive.NorthAmerica
+	}
+	if expiresAfter <= 0 {
+		expiresAfter = DefaultExpiry
+	}
+
+	service := &askgo.ServiceClient{Endpoint: endpoint, Token: token, HTTPClient: client.HTTPClient}
+	err = service.Do(ctx, "POST", "/v1/skillmessages/users/"+url.PathEscape(userID),
+		&message{Data: data, ExpiresAfterSeconds: int(expiresAfter / time.Second)}, nil)
+
+	var serviceErr *askgo.ServiceError
+	if errors.As(err, &serviceErr) && serviceErr.StatusCode == http.StatusUnauthorized {
+		client.LWA.Invalidate(Scope)
+	}
+	return err
+}
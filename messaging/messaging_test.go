@@ -0,0 +1,68 @@
+package messaging_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/lwa"
+	"github.com/koblas/askgo/messaging"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SendAndReceive(t *testing.T) {
+	persistence := &askgo.MemoryPersistence{}
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		Persistence:     &askgo.Persistence{Adapter: persistence},
+		Handlers: []askgo.RequestHandler{askgo.RequestRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+			attributes, err := askgo.GetPersistentAttributes(input)
+			if err != nil {
+				return nil, err
+			}
+			attributes["quiz"] = input.GetRequest().Message["quiz"]
+			return nil, askgo.SavePersistentAttributes(input)
+		}, messaging.MessageReceived)},
+	}
+
+	auth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, messaging.Scope, r.PostForm.Get("scope"))
+		w.Write([]byte(`{"access_token": "Atc|token", "expires_in": 3600}`))
+	}))
+	defer auth.Close()
+
+	// The stand-in for the API delivers the message to the skill as Alexa would
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer Atc|token", r.Header.Get("Authorization"))
+		require.Equal(t, "/v1/skillmessages/users/amzn1.ask.account.1", r.URL.Path)
+
+		var body struct {
+			Data                map[string]interface{} `json:"data"`
+			ExpiresAfterSeconds int                    `json:"expiresAfterSeconds"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, 600, body.ExpiresAfterSeconds)
+
+		envelope := &askgo.RequestEnvelope{Version: "1.0"}
+		envelope.Request.Type = messaging.MessageReceived
+		envelope.Request.Message = body.Data
+		envelope.Context.System.User.UserID = "amzn1.ask.account.1"
+		_, err := skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), envelope))
+		require.NoError(t, err)
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer api.Close()
+
+	client := &messaging.Client{LWA: &lwa.Client{ClientID: "id", ClientSecret: "secret", TokenURL: auth.URL}, Endpoint: api.URL}
+	require.NoError(t, client.Send(context.Background(), "amzn1.ask.account.1", map[string]interface{}{"quiz": "weekly"}, 10*time.Minute))
+
+	attributes, err := persistence.GetAttributes(context.Background(), "amzn1.ask.account.1")
+	require.NoError(t, err)
+	require.Equal(t, "weekly", attributes["quiz"])
+}
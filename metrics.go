@@ -0,0 +1,167 @@
+package askgo
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricLabels are the label values of a measurement
+type MetricLabels map[string]string
+
+// Metrics receives the measurements of request processing, the metrics package provides
+// a Prometheus exporter and an in-memory recorder
+type Metrics interface {
+	// Count increments the counter by one
+	Count(name string, labels MetricLabels)
+	// Observe records a value (e.g. a duration in seconds) in a histogram
+	Observe(name string, value float64, labels MetricLabels)
+}
+
+// Metric names
+const (
+	// MetricRequests counts the processed requests by request type, intent, handler and outcome
+	MetricRequests = "askgo_requests_total"
+	// MetricRequestDuration is the time taken by ProcessRequest in seconds
+	MetricRequestDuration = "askgo_request_duration_seconds"
+	// MetricHandlerDuration is the time taken by the request handler in seconds
+	MetricHandlerDuration = "askgo_handler_duration_seconds"
+	// MetricInterceptorDuration is the time taken by each interceptor in seconds, by interceptor and phase
+	MetricInterceptorDuration = "askgo_interceptor_duration_seconds"
+	// MetricErrors counts the errors dispatched to the error handlers by request type, intent,
+	// handler and error handler ("none" when no error handler accepted the error)
+	MetricErrors = "askgo_errors_total"
+)
+
+// Metric label names
+const (
+	LabelRequestType  = "request_type"
+	LabelIntent       = "intent"
+	LabelHandler      = "handler"
+	LabelOutcome      = "outcome"
+	LabelInterceptor  = "interceptor"
+	LabelPhase        = "phase"
+	LabelErrorHandler = "error_handler"
+)
+
+// Values of the outcome label
+const (
+	// OutcomeSuccess is a response produced by a request handler
+	OutcomeSuccess = "success"
+	// OutcomeNoHandler is a request no handler could handle
+	OutcomeNoHandler = "no_handler"
+	// OutcomeFallback is a response produced by the fallback handler
+	OutcomeFallback = "fallback"
+	// OutcomeHandledError is an error turned into a response by an error handler
+	OutcomeHandledError = "handled_error"
+	// OutcomeError is an error returned by ProcessRequest
+	OutcomeError = "error"
+	// OutcomeTimeout is a request that was not answered before the deadline
+	OutcomeTimeout = "timeout"
+	// OutcomeRejected is a request that failed the application ID or timestamp verification
+	OutcomeRejected = "rejected"
+)
+
+// HandlerNamer is implemented by handlers and interceptors to name themselves in metrics,
+// otherwise the name of their type is used
+type HandlerNamer interface {
+	HandlerName() string
+}
+
+// HandlerName returns the name of a handler or interceptor used in metrics
+func HandlerName(handler interface{}) string {
+	if handler == nil {
+		return "none"
+	}
+	if namer, ok := handler.(HandlerNamer); ok {
+		return namer.HandlerName()
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", handler), "*")
+}
+
+// funcName returns the package qualified name of the function (e.g. "quiz.startQuiz")
+func funcName(fn interface{}) string {
+	value := reflect.ValueOf(fn)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return ""
+	}
+	name := runtime.FuncForPC(value.Pointer()).Name()
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return name
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) Count(string, MetricLabels)            {}
+func (nopMetrics) Observe(string, float64, MetricLabels) {}
+
+// requestMetrics tracks the measurements of a single request, a request that timed out
+// is still being processed while the timeout is handled
+type requestMetrics struct {
+	metrics     Metrics
+	start       time.Time
+	requestType string
+	intent      string
+
+	mutex   sync.Mutex
+	handler string
+}
+
+func newRequestMetrics(metrics Metrics, request Request) *requestMetrics {
+	if metrics == nil {
+		metrics = nopMetrics{}
+	}
+	return &requestMetrics{
+		metrics:     metrics,
+		start:       time.Now(),
+		requestType: request.Type,
+		intent:      request.Intent.Name,
+		handler:     "none",
+	}
+}
+
+func (m *requestMetrics) setHandler(name string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.handler = name
+}
+
+func (m *requestMetrics) handlerName() string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.handler
+}
+
+func (m *requestMetrics) labels(extra ...string) MetricLabels {
+	labels := MetricLabels{LabelRequestType: m.requestType, LabelIntent: m.intent}
+	for idx := 0; idx+1 < len(extra); idx += 2 {
+		labels[extra[idx]] = extra[idx+1]
+	}
+	return labels
+}
+
+func (m *requestMetrics) interceptor(interceptor interface{}, phase string, start time.Time) {
+	m.metrics.Observe(MetricInterceptorDuration, time.Since(start).Seconds(),
+		m.labels(LabelInterceptor, HandlerName(interceptor), LabelPhase, phase))
+}
+
+func (m *requestMetrics) handled(start time.Time) {
+	m.metrics.Observe(MetricHandlerDuration, time.Since(start).Seconds(), m.labels(LabelHandler, m.handlerName()))
+}
+
+func (m *requestMetrics) dispatched(errorHandler interface{}) {
+	m.metrics.Count(MetricErrors, m.labels(LabelHandler, m.handlerName(), LabelErrorHandler, HandlerName(errorHandler)))
+}
+
+func (m *requestMetrics) done(outcome string) {
+	labels := m.labels(LabelHandler, m.handlerName(), LabelOutcome, outcome)
+	m.metrics.Count(MetricRequests, labels)
+	m.metrics.Observe(MetricRequestDuration, time.Since(m.start).Seconds(), labels)
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/koblas/askgo"
+)
+
+// Memory records every measurement, for use in tests
+type Memory struct {
+	mutex   sync.Mutex
+	samples []sample
+}
+
+type sample struct {
+	name    string
+	labels  askgo.MetricLabels
+	value   float64
+	counter bool
+}
+
+var _ askgo.Metrics = &Memory{}
+
+// Count implements askgo.Metrics
+func (m *Memory) Count(name string, labels askgo.MetricLabels) {
+	m.add(sample{name: name, labels: labels, value: 1, counter: true})
+}
+
+// Observe implements askgo.Metrics
+func (m *Memory) Observe(name string, value float64, labels askgo.MetricLabels) {
+	m.add(sample{name: name, labels: labels, value: value})
+}
+
+func (m *Memory) add(s sample) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.samples = append(m.samples, s)
+}
+
+// Counter returns the total of the counter for the series having all the given labels
+func (m *Memory) Counter(name string, labels askgo.MetricLabels) float64 {
+	total := 0.0
+	for _, s := range m.find(name, labels, true) {
+		total += s.value
+	}
+	return total
+}
+
+// Observations returns the values observed for the series having all the given labels
+func (m *Memory) Observations(name string, labels askgo.MetricLabels) []float64 {
+	values := make([]float64, 0)
+	for _, s := range m.find(name, labels, false) {
+		values = append(values, s.value)
+	}
+	return values
+}
+
+// Reset removes every measurement
+func (m *Memory) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.samples = nil
+}
+
+func (m *Memory) find(name string, labels askgo.MetricLabels, counter bool) []sample {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	found := make([]sample, 0)
+	for _, s := range m.samples {
+		if s.name != name || s.counter != counter {
+			continue
+		}
+		matches := true
+		for key, value := range labels {
+			if s.labels[key] != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			found = append(found, s)
+		}
+	}
+	return found
+}
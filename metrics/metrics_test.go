@@ -0,0 +1,141 @@
+package metrics_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/koblas/askgo/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+type errorHandler struct{}
+
+func (errorHandler) CanHandle(input askgo.HandlerInput, err error) bool { return true }
+
+func (errorHandler) Handle(input askgo.HandlerInput, err error) (*askgo.ResponseEnvelope, error) {
+	return input.GetResponse().Speak("Sorry"), nil
+}
+
+func hello(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+	return input.GetResponse().Speak("Hello"), nil
+}
+
+func process(t *testing.T, skill *askgo.Skill, requestType, intent string) {
+	envelope := &askgo.RequestEnvelope{Request: alexa.Request{Type: requestType, Intent: alexa.Intent{Name: intent}}}
+	_, err := skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), envelope))
+	require.NoError(t, err)
+}
+
+func newSkill(recorder askgo.Metrics) *askgo.Skill {
+	return &askgo.Skill{
+		IgnoreTimestamp: true,
+		Metrics:         recorder,
+		Handlers: []askgo.RequestHandler{
+			askgo.RequestRoute(hello, "LaunchRequest"),
+			&askgo.Route{Name: "fail", Intents: []string{"FailIntent"}, Handler: func(askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				return nil, errors.New("failed")
+			}},
+		},
+		ErrorHandlers: []askgo.ErrorHandler{errorHandler{}},
+	}
+}
+
+func Test_Memory(t *testing.T) {
+	recorder := &metrics.Memory{}
+	skill := newSkill(recorder)
+
+	process(t, skill, "LaunchRequest", "")
+	process(t, skill, "LaunchRequest", "")
+	process(t, skill, "IntentRequest", "FailIntent")
+	process(t, skill, "IntentRequest", "OtherIntent")
+
+	require.Equal(t, 4.0, recorder.Counter(askgo.MetricRequests, nil))
+	require.Equal(t, 2.0, recorder.Counter(askgo.MetricRequests, askgo.MetricLabels{
+		askgo.LabelRequestType: "LaunchRequest",
+		askgo.LabelHandler:     "metrics_test.hello",
+		askgo.LabelOutcome:     askgo.OutcomeSuccess,
+	}))
+	require.Equal(t, 1.0, recorder.Counter(askgo.MetricRequests, askgo.MetricLabels{
+		askgo.LabelIntent:  "FailIntent",
+		askgo.LabelHandler: "fail",
+		askgo.LabelOutcome: askgo.OutcomeHandledError,
+	}))
+	require.Equal(t, 1.0, recorder.Counter(askgo.MetricRequests, askgo.MetricLabels{
+		askgo.LabelIntent:  "OtherIntent",
+		askgo.LabelHandler: "none",
+		askgo.LabelOutcome: askgo.OutcomeNoHandler,
+	}))
+	require.Equal(t, 1.0, recorder.Counter(askgo.MetricErrors, askgo.MetricLabels{
+		askgo.LabelIntent:       "FailIntent",
+		askgo.LabelErrorHandler: "metrics_test.errorHandler",
+	}))
+	require.Equal(t, 2.0, recorder.Counter(askgo.MetricErrors, askgo.MetricLabels{
+		askgo.LabelErrorHandler: "metrics_test.errorHandler",
+	}))
+	require.Len(t, recorder.Observations(askgo.MetricHandlerDuration, nil), 3)
+	require.Len(t, recorder.Observations(askgo.MetricRequestDuration, askgo.MetricLabels{askgo.LabelRequestType: "LaunchRequest"}), 2)
+}
+
+func Test_Prometheus(t *testing.T) {
+	exporter := &metrics.Prometheus{Buckets: []float64{0.5, 1}}
+	exporter.Count("requests_total", askgo.MetricLabels{"intent": `Say "hi"`})
+	exporter.Count("requests_total", askgo.MetricLabels{"intent": `Say "hi"`})
+	exporter.Observe("duration_seconds", 0.75, nil)
+	exporter.Observe("duration_seconds", 0.25, nil)
+
+	buf := &bytes.Buffer{}
+	_, err := exporter.WriteTo(buf)
+	require.NoError(t, err)
+	require.Equal(t, strings.Join([]string{
+		`# TYPE requests_total counter`,
+		`requests_total{intent="Say \"hi\""} 2`,
+		`# TYPE duration_seconds histogram`,
+		`duration_seconds_bucket{le="0.5"} 1`,
+		`duration_seconds_bucket{le="1"} 2`,
+		`duration_seconds_bucket{le="+Inf"} 2`,
+		`duration_seconds_sum 1`,
+		`duration_seconds_count 2`,
+		``,
+	}, "\n"), buf.String())
+
+	exporter = metrics.NewPrometheus()
+	process(t, newSkill(exporter), "LaunchRequest", "")
+	buf.Reset()
+	exporter.WriteTo(buf)
+	require.Contains(t, buf.String(), `askgo_requests_total{handler="metrics_test.hello",intent="",outcome="success",request_type="LaunchRequest"} 1`)
+	require.Contains(t, buf.String(), `askgo_handler_duration_seconds_bucket{handler="metrics_test.hello",intent="",request_type="LaunchRequest",le="+Inf"} 1`)
+}
+
+func Test_PrometheusBucketsChanged(t *testing.T) {
+	exporter := &metrics.Prometheus{Buckets: []float64{0.5}}
+	exporter.Observe("duration_seconds", 0.25, nil)
+
+	// Series keep the buckets they were created with
+	exporter.Buckets = []float64{0.1, 0.5, 1}
+	exporter.Observe("duration_seconds", 0.75, nil)
+	exporter.Observe("latency_seconds", 0.75, nil)
+
+	buf := &bytes.Buffer{}
+	_, err := exporter.WriteTo(buf)
+	require.NoError(t, err)
+	require.Equal(t, strings.Join([]string{
+		`# TYPE duration_seconds histogram`,
+		`duration_seconds_bucket{le="0.5"} 1`,
+		`duration_seconds_bucket{le="+Inf"} 2`,
+		`duration_seconds_sum 1`,
+		`duration_seconds_count 2`,
+		`# TYPE latency_seconds histogram`,
+		`latency_seconds_bucket{le="0.1"} 0`,
+		`latency_seconds_bucket{le="0.5"} 0`,
+		`latency_seconds_bucket{le="1"} 1`,
+		`latency_seconds_bucket{le="+Inf"} 1`,
+		`latency_seconds_sum 0.75`,
+		`latency_seconds_count 1`,
+		``,
+	}, "\n"), buf.String())
+}
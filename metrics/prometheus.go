@@ -0,0 +1,222 @@
+// Package metrics provides implementations of askgo.Metrics: a Prometheus exporter and an
+// in-memory recorder for tests.
+//
+//	exporter := metrics.NewPrometheus()
+//	skill.Metrics = exporter
+//	http.Handle("/metrics", exporter)
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/koblas/askgo"
+)
+
+// DefaultBuckets are the histogram bucket upper bounds in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Prometheus aggregates the metrics in memory and exposes them in the Prometheus text format
+type Prometheus struct {
+	// Buckets of the histograms, must be sorted, defaults to DefaultBuckets
+	Buckets []float64
+
+	mutex      sync.Mutex
+	counters   map[string]map[string]float64
+	histograms map[string]map[string]*histogram
+}
+
+// histogram keeps the bucket bounds it was created with, later changes to Buckets only apply
+// to new series
+type histogram struct {
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+var _ askgo.Metrics = &Prometheus{}
+var _ http.Handler = &Prometheus{}
+
+// NewPrometheus creates an empty exporter
+func NewPrometheus() *Prometheus {
+	return &Prometheus{
+		counters:   make(map[string]map[string]float64),
+		histograms: make(map[string]map[string]*histogram),
+	}
+}
+
+// Count implements askgo.Metrics
+func (p *Prometheus) Count(name string, labels askgo.MetricLabels) {
+	key := formatLabels(labels)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.counters == nil {
+		p.counters = make(map[string]map[string]float64)
+	}
+	series, found := p.counters[name]
+	if !found {
+		series = make(map[string]float64)
+		p.counters[name] = series
+	}
+	series[key]++
+}
+
+// Observe implements askgo.Metrics
+func (p *Prometheus) Observe(name string, value float64, labels askgo.MetricLabels) {
+	key := formatLabels(labels)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.histograms == nil {
+		p.histograms = make(map[string]map[string]*histogram)
+	}
+	series, found := p.histograms[name]
+	if !found {
+		series = make(map[string]*histogram)
+		p.histograms[name] = series
+	}
+	hist, found := series[key]
+	if !found {
+		bounds := append([]float64(nil), p.buckets()...)
+		hist = &histogram{bounds: bounds, counts: make([]uint64, len(bounds))}
+		series[key] = hist
+	}
+
+	for idx, bound := range hist.bounds {
+		if value <= bound {
+			hist.counts[idx]++
+		}
+	}
+	hist.count++
+	hist.sum += value
+}
+
+func (p *Prometheus) buckets() []float64 {
+	if len(p.Buckets) != 0 {
+		return p.Buckets
+	}
+	return DefaultBuckets
+}
+
+// WriteTo writes every metric in the Prometheus text exposition format
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{writer: bufio.NewWriter(w)}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, name := range sortedKeys(p.counters) {
+		series := p.counters[name]
+		fmt.Fprintf(counter, "# TYPE %s counter\n", name)
+		for _, key := range sortedKeys(series) {
+			fmt.Fprintf(counter, "%s%s %s\n", name, key, formatFloat(series[key]))
+		}
+	}
+
+	for _, name := range sortedKeys(p.histograms) {
+		series := p.histograms[name]
+		fmt.Fprintf(counter, "# TYPE %s histogram\n", name)
+		for _, key := range sortedKeys(series) {
+			hist := series[key]
+			for idx, bound := range hist.bounds {
+				fmt.Fprintf(counter, "%s_bucket%s %d\n", name, withLabel(key, "le", formatFloat(bound)), hist.counts[idx])
+			}
+			fmt.Fprintf(counter, "%s_bucket%s %d\n", name, withLabel(key, "le", "+Inf"), hist.count)
+			fmt.Fprintf(counter, "%s_sum%s %s\n", name, key, formatFloat(hist.sum))
+			fmt.Fprintf(counter, "%s_count%s %d\n", name, key, hist.count)
+		}
+	}
+
+	if err := counter.writer.Flush(); err != nil {
+		return counter.count, err
+	}
+	return counter.count, counter.err
+}
+
+// ServeHTTP serves the metrics to a Prometheus scraper
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	p.WriteTo(w)
+}
+
+// Reset removes every metric
+func (p *Prometheus) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.counters = make(map[string]map[string]float64)
+	p.histograms = make(map[string]map[string]*histogram)
+}
+
+type countingWriter struct {
+	writer *bufio.Writer
+	count  int64
+	err    error
+}
+
+func (w *countingWriter) Write(data []byte) (int, error) {
+	n, err := w.writer.Write(data)
+	w.count += int64(n)
+	if err != nil && w.err == nil {
+		w.err = err
+	}
+	return n, err
+}
+
+// formatLabels renders the labels sorted by name
+func formatLabels(labels askgo.MetricLabels) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+`="`+escapeLabel(labels[name])+`"`)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// withLabel appends a label to formatted labels
+func withLabel(key, name, value string) string {
+	label := name + `="` + escapeLabel(value) + `"`
+	if key == "" {
+		return "{" + label + "}"
+	}
+	return key[:len(key)-1] + "," + label + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// sortedKeys returns the keys of a map with string keys in order
+func sortedKeys(m interface{}) []string {
+	keys := make([]string, 0)
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
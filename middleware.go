@@ -0,0 +1,26 @@
+package askgo
+
+// Middleware wraps a handler, it can run code before and after calling next, call it
+// several times (e.g. to retry) or not at all and return its own response.
+//
+//	func timing(next askgo.HandlerFunc) askgo.HandlerFunc {
+//		return func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+//			start := time.Now()
+//			defer func() { input.GetLogger().Info("handled", "elapsed", time.Since(start)) }()
+//			return next(input)
+//		}
+//	}
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Chain wraps the handler with the middleware, the first middleware is the outermost
+func Chain(handler HandlerFunc, middleware ...Middleware) HandlerFunc {
+	return ChainOf(handler, middleware...)
+}
+
+// ChainOf is Chain for the handler types of other routers (e.g. smarthome.Router)
+func ChainOf[F any, M ~func(F) F](handler F, middleware ...M) F {
+	for idx := len(middleware) - 1; idx >= 0; idx-- {
+		handler = middleware[idx](handler)
+	}
+	return handler
+}
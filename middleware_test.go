@@ -0,0 +1,108 @@
+package askgo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/stretchr/testify/require"
+)
+
+type orderInterceptor struct {
+	calls *[]string
+}
+
+func (interceptor orderInterceptor) Process(input askgo.HandlerInput) error {
+	*interceptor.calls = append(*interceptor.calls, "request")
+	return nil
+}
+
+func tracing(calls *[]string, name string) askgo.Middleware {
+	return func(next askgo.HandlerFunc) askgo.HandlerFunc {
+		return func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+			*calls = append(*calls, name+" before")
+			response, err := next(input)
+			*calls = append(*calls, name+" after")
+			return response, err
+		}
+	}
+}
+
+func Test_MiddlewareOrder(t *testing.T) {
+	calls := []string{}
+	skill := &askgo.Skill{
+		IgnoreTimestamp:     true,
+		RequestInterceptors: []askgo.RequestInterceptor{orderInterceptor{&calls}},
+		Middleware:          []askgo.Middleware{tracing(&calls, "outer"), tracing(&calls, "inner")},
+		Handlers: []askgo.RequestHandler{&askgo.Route{
+			Intents:    []string{alexa.HelpIntent},
+			Middleware: []askgo.Middleware{tracing(&calls, "route")},
+			Handler: func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				calls = append(calls, "handler")
+				return input.GetResponse().Speak("Help"), nil
+			},
+		}},
+	}
+
+	_, err := skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), intentRequest(alexa.HelpIntent)))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"request", "outer before", "inner before", "route before", "handler", "route after", "inner after", "outer after",
+	}, calls)
+}
+
+func Test_MiddlewareShortCircuit(t *testing.T) {
+	called := false
+	saver := &attributeSaver{}
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		Middleware: []askgo.Middleware{func(next askgo.HandlerFunc) askgo.HandlerFunc {
+			return func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				return input.GetResponse().Speak("Not now"), nil
+			}
+		}},
+		Handlers: []askgo.RequestHandler{askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+			called = true
+			return nil, nil
+		}, alexa.HelpIntent)},
+		ResponseInterceptors: []askgo.ResponseInterceptor{saver},
+	}
+
+	// The middleware answers even requests no handler can handle
+	for _, intent := range []string{alexa.HelpIntent, "OrderIntent"} {
+		_, err := skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), intentRequest(intent)))
+		require.NoError(t, err)
+	}
+	require.False(t, called)
+	require.Len(t, saver.seen, 2)
+}
+
+func Test_MiddlewareRetry(t *testing.T) {
+	attempts := 0
+	retry := func(next askgo.HandlerFunc) askgo.HandlerFunc {
+		return func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+			response, err := next(input)
+			if err != nil {
+				response, err = next(input)
+			}
+			return response, err
+		}
+	}
+	handler := askgo.Chain(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("flaky")
+		}
+		return input.GetResponse().Speak("Done"), nil
+	}, retry)
+
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		Handlers:        []askgo.RequestHandler{askgo.IntentRoute(handler, alexa.HelpIntent)},
+	}
+	_, err := skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), intentRequest(alexa.HelpIntent)))
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+}
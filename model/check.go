@@ -0,0 +1,181 @@
+package model
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+)
+
+// ProblemKind is the kind of mismatch between the model and the handlers
+type ProblemKind int
+
+const (
+	// UnknownIntent is an intent referenced by a handler that is not in the model
+	UnknownIntent ProblemKind = iota
+	// UnhandledIntent is an intent in the model that no handler can handle
+	UnhandledIntent
+	// CheckFailed is an intent that could not be checked, an interceptor or handler failed
+	// or panicked on the synthetic request
+	CheckFailed
+)
+
+// Problem is a mismatch between the interaction model and the handlers of a skill
+type Problem struct {
+	Kind   ProblemKind
+	Intent string
+	// Handler is the name of the handler referencing an unknown intent, or of the
+	// interceptor or handler that failed
+	Handler string
+	// Err is the failure of a CheckFailed problem, panics are an askgo.PanicError
+	Err error
+}
+
+func (problem Problem) String() string {
+	switch problem.Kind {
+	case UnknownIntent:
+		return fmt.Sprintf("handler %s references intent %s which is not in the model", problem.Handler, problem.Intent)
+	case CheckFailed:
+		return fmt.Sprintf("intent %s could not be checked, %s failed: %v", problem.Intent, problem.Handler, problem.Err)
+	}
+	return fmt.Sprintf("intent %s has no handler", problem.Intent)
+}
+
+// CheckOptions change how Check offers the intents to the skill
+type CheckOptions struct {
+	// RequestInterceptors runs the request interceptors of the skill before offering each intent,
+	// for handlers that depend on what they load.  They are given a synthetic request and must not
+	// have side effects (e.g. API calls or persistence writes) that matter for it.
+	RequestInterceptors bool
+}
+
+// Check compares the model to the handlers of the skill with the default options
+func Check(schema *Schema, skill *askgo.Skill) []Problem {
+	return CheckWithOptions(schema, skill, CheckOptions{})
+}
+
+// CheckWithOptions compares the model to the handlers of the skill.
+//
+// Handlers implementing askgo.IntentLister (e.g. askgo.Route) are checked for intents that are
+// not in the model.  Every intent of the model is offered to the handlers as an IntentRequest to
+// find the intents that no handler accepts:
+//   - the FallbackHandler of the skill and the Unhandled handlers of an askgo.StateMachine are not
+//     consulted, an intent only they would answer is reported as unhandled
+//   - the intents of an askgo.StateMachine are offered in each of its states
+//   - AMAZON.RepeatIntent is handled when the skill has a Repeat
+func CheckWithOptions(schema *Schema, skill *askgo.Skill, options CheckOptions) []Problem {
+	problems := make([]Problem, 0)
+
+	for _, handler := range skill.Handlers {
+		lister, ok := handler.(askgo.IntentLister)
+		if !ok {
+			continue
+		}
+		for _, name := range lister.HandledIntents() {
+			if schema.FindIntent(name) == nil {
+				problems = append(problems, Problem{Kind: UnknownIntent, Intent: name, Handler: askgo.HandlerName(handler)})
+			}
+		}
+	}
+
+	for _, intent := range schema.InteractionModel.LanguageModel.Intents {
+		if skill.Repeat != nil && intent.Name == alexa.RepeatIntent {
+			continue
+		}
+		if handled, problem := canHandle(skill, options, intent.Name); problem != nil {
+			problems = append(problems, *problem)
+		} else if !handled {
+			problems = append(problems, Problem{Kind: UnhandledIntent, Intent: intent.Name})
+		}
+	}
+
+	return problems
+}
+
+// canHandle offers the intent to the handlers, it returns a CheckFailed problem when an
+// interceptor or handler fails
+func canHandle(skill *askgo.Skill, options CheckOptions, name string) (bool, *Problem) {
+	for _, handler := range skill.Handlers {
+		if machine, ok := handler.(*askgo.StateMachine); ok {
+			if handled, problem := machineCanHandle(skill, options, machine, name); handled || problem != nil {
+				return handled, problem
+			}
+			continue
+		}
+
+		input, problem := newCheckInput(skill, options, name, nil)
+		if problem != nil {
+			return false, problem
+		}
+		if handled, problem := offer(input, handler, name); handled || problem != nil {
+			return handled, problem
+		}
+	}
+
+	return false, nil
+}
+
+// machineCanHandle offers the intent to the handlers of each state of the machine, the Help
+// handler of a state is counted for AMAZON.HelpIntent
+func machineCanHandle(skill *askgo.Skill, options CheckOptions, machine *askgo.StateMachine, name string) (bool, *Problem) {
+	attribute := machine.Attribute
+	if attribute == "" {
+		attribute = askgo.StateAttribute
+	}
+
+	for _, state := range machine.States {
+		if state.Help != nil && name == alexa.HelpIntent {
+			return true, nil
+		}
+
+		input, problem := newCheckInput(skill, options, name, map[string]interface{}{attribute: state.Name})
+		if problem != nil {
+			return false, problem
+		}
+		for _, handler := range state.Handlers {
+			if handled, problem := offer(input, handler, name); handled || problem != nil {
+				return handled, problem
+			}
+		}
+	}
+	return false, nil
+}
+
+// offer asks the handler whether it can handle the input, a panic is a CheckFailed problem
+func offer(input askgo.HandlerInput, handler askgo.RequestHandler, name string) (bool, *Problem) {
+	var handled bool
+	if err := askgo.Capture(func() error {
+		handled = handler.CanHandle(input)
+		return nil
+	}); err != nil {
+		return false, &Problem{Kind: CheckFailed, Intent: name, Handler: askgo.HandlerName(handler), Err: err}
+	}
+	return handled, nil
+}
+
+// newCheckInput returns a synthetic IntentRequest, after running the request interceptors
+// when the options ask for it
+func newCheckInput(skill *askgo.Skill, options CheckOptions, name string, attributes map[string]interface{}) (askgo.HandlerInput, *Problem) {
+	if attributes == nil {
+		attributes = map[string]interface{}{}
+	}
+	envelope := &askgo.RequestEnvelope{
+		Version: "1.0",
+		Session: alexa.Session{Attributes: attributes},
+		Request: askgo.Request{
+			Type:   "IntentRequest",
+			Intent: alexa.Intent{Name: name, Slots: map[string]alexa.IntentSlot{}},
+		},
+	}
+	input := askgo.NewDefaultHandler(context.Background(), envelope)
+
+	if options.RequestInterceptors {
+		for _, interceptor := range skill.RequestInterceptors {
+			if err := askgo.Capture(func() error { return interceptor.Process(input) }); err != nil {
+				return nil, &Problem{Kind: CheckFailed, Intent: name, Handler: askgo.HandlerName(interceptor), Err: err}
+			}
+		}
+	}
+	return input, nil
+}
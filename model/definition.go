@@ -0,0 +1,246 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Localized holds values per locale.  Lookups fall back from the locale to its
+// language and then to the "" entry, so Localized{"": {...}} applies to every locale.
+type Localized map[string][]string
+
+// Get returns the values for the locale
+func (l Localized) Get(locale string) []string {
+	if values, found := l[locale]; found {
+		return values
+	}
+	if idx := strings.Index(locale, "-"); idx > 0 {
+		if values, found := l[locale[:idx]]; found {
+			return values
+		}
+	}
+	return l[""]
+}
+
+// Definition is the interaction model of a skill for all of its locales
+type Definition struct {
+	// InvocationName by locale, a schema is generated for each of these locales
+	InvocationName map[string]string
+	Intents        []IntentDefinition
+	Types          []SlotTypeDefinition
+	// DelegationStrategy for the dialog model (ALWAYS or SKILL_RESPONSE)
+	DelegationStrategy string
+}
+
+// IntentDefinition declares an intent, built-in intents (e.g. alexa.HelpIntent) need no samples
+type IntentDefinition struct {
+	Name    string
+	Samples Localized
+	Slots   []SlotDefinition
+
+	// ConfirmationRequired has Alexa confirm the intent using the Confirmation prompts
+	ConfirmationRequired bool
+	Confirmation         Localized
+}
+
+// SlotDefinition declares a slot of an intent
+type SlotDefinition struct {
+	Name string
+	Type string
+	// Samples are utterances used when eliciting the slot (e.g. "my favorite color is {Color}")
+	Samples Localized
+
+	// ElicitationRequired has Alexa ask for the slot using the Elicitation prompts
+	ElicitationRequired bool
+	Elicitation         Localized
+	// ConfirmationRequired has Alexa confirm the slot using the Confirmation prompts
+	ConfirmationRequired bool
+	Confirmation         Localized
+}
+
+// SlotTypeDefinition declares a custom slot type
+type SlotTypeDefinition struct {
+	Name   string
+	Values []SlotValueDefinition
+}
+
+// SlotValueDefinition is a value of a custom slot type
+type SlotValueDefinition struct {
+	// ID is returned in the entity resolution of the slot
+	ID string
+	// Value by locale, the "" entry applies to every locale
+	Value map[string]string
+	// Synonyms by locale
+	Synonyms Localized
+}
+
+var sampleSlotRe = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Locales returns the locales of the definition in sorted order
+func (def *Definition) Locales() []string {
+	locales := make([]string, 0, len(def.InvocationName))
+	for locale := range def.InvocationName {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Schema generates the interaction model for a locale
+func (def *Definition) Schema(locale string) (*Schema, error) {
+	invocation, found := def.InvocationName[locale]
+	if !found {
+		return nil, fmt.Errorf("model: no invocation name for %s", locale)
+	}
+
+	schema := &Schema{}
+	lm := &schema.InteractionModel.LanguageModel
+	lm.InvocationName = invocation
+	lm.Intents = make([]Intent, 0, len(def.Intents))
+
+	customTypes := make(map[string]bool)
+	for _, slotType := range def.Types {
+		customTypes[slotType.Name] = true
+	}
+
+	dialog := &Dialog{DelegationStrategy: def.DelegationStrategy}
+	prompts := make([]Prompt, 0)
+	addPrompt := func(id string, values []string) {
+		prompt := Prompt{ID: id}
+		for _, value := range values {
+			prompt.Variations = append(prompt.Variations, variation(value))
+		}
+		prompts = append(prompts, prompt)
+	}
+
+	for _, intentDef := range def.Intents {
+		intent := Intent{Name: intentDef.Name, Samples: intentDef.Samples.Get(locale)}
+		if intent.Samples == nil {
+			intent.Samples = []string{}
+		}
+
+		slotNames := make(map[string]bool)
+		for _, slotDef := range intentDef.Slots {
+			if !strings.HasPrefix(slotDef.Type, "AMAZON.") && !customTypes[slotDef.Type] {
+				return nil, fmt.Errorf("model: slot %s of %s has undefined type %s", slotDef.Name, intentDef.Name, slotDef.Type)
+			}
+			slotNames[slotDef.Name] = true
+			intent.Slots = append(intent.Slots, Slot{
+				Name:    slotDef.Name,
+				Type:    slotDef.Type,
+				Samples: slotDef.Samples.Get(locale),
+			})
+		}
+
+		for _, sample := range intent.Samples {
+			for _, match := range sampleSlotRe.FindAllStringSubmatch(sample, -1) {
+				if !slotNames[match[1]] {
+					return nil, fmt.Errorf("model: sample %q of %s references unknown slot %s", sample, intentDef.Name, match[1])
+				}
+			}
+		}
+
+		lm.Intents = append(lm.Intents, intent)
+
+		// Only intents that use the dialog model are added to it
+		dialogIntent := DialogIntent{
+			Name:                 intentDef.Name,
+			ConfirmationRequired: intentDef.ConfirmationRequired,
+			Prompts:              map[string]string{},
+			Slots:                []DialogSlot{},
+		}
+		usesDialog := intentDef.ConfirmationRequired
+		if intentDef.ConfirmationRequired {
+			id := "Confirm.Intent." + intentDef.Name
+			dialogIntent.Prompts["confirmation"] = id
+			addPrompt(id, intentDef.Confirmation.Get(locale))
+		}
+		for _, slotDef := range intentDef.Slots {
+			dialogSlot := DialogSlot{
+				Name:                 slotDef.Name,
+				Type:                 slotDef.Type,
+				ElicitationRequired:  slotDef.ElicitationRequired,
+				ConfirmationRequired: slotDef.ConfirmationRequired,
+				Prompts:              map[string]string{},
+			}
+			if slotDef.ElicitationRequired {
+				id := "Elicit.Slot." + intentDef.Name + "." + slotDef.Name
+				dialogSlot.Prompts["elicitation"] = id
+				addPrompt(id, slotDef.Elicitation.Get(locale))
+			}
+			if slotDef.ConfirmationRequired {
+				id := "Confirm.Slot." + intentDef.Name + "." + slotDef.Name
+				dialogSlot.Prompts["confirmation"] = id
+				addPrompt(id, slotDef.Confirmation.Get(locale))
+			}
+			usesDialog = usesDialog || slotDef.ElicitationRequired || slotDef.ConfirmationRequired
+			dialogIntent.Slots = append(dialogIntent.Slots, dialogSlot)
+		}
+		if usesDialog {
+			dialog.Intents = append(dialog.Intents, dialogIntent)
+		}
+	}
+
+	for _, typeDef := range def.Types {
+		slotType := SlotType{Name: typeDef.Name, Values: []SlotTypeValue{}}
+		for _, valueDef := range typeDef.Values {
+			value, found := valueDef.Value[locale]
+			if !found {
+				value = valueDef.Value[""]
+			}
+			if value == "" {
+				continue
+			}
+			slotType.Values = append(slotType.Values, SlotTypeValue{
+				ID: valueDef.ID,
+				Name: SlotTypeValueName{
+					Value:    value,
+					Synonyms: valueDef.Synonyms.Get(locale),
+				},
+			})
+		}
+		lm.Types = append(lm.Types, slotType)
+	}
+
+	if len(dialog.Intents) != 0 {
+		schema.InteractionModel.Dialog = dialog
+		schema.InteractionModel.Prompts = prompts
+	}
+
+	return schema, nil
+}
+
+// WriteDir generates the schema for every locale and writes it to <dir>/<locale>.json
+func (def *Definition) WriteDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, locale := range def.Locales() {
+		schema, err := def.Schema(locale)
+		if err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, locale+".json"), append(data, '\n'), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func variation(value string) PromptVariation {
+	if strings.HasPrefix(strings.TrimSpace(value), "<speak>") {
+		return PromptVariation{Type: "SSML", Value: value}
+	}
+	return PromptVariation{Type: "PlainText", Value: value}
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// Load reads an interaction model JSON document (as exported from the developer console)
+func Load(reader io.Reader) (*Schema, error) {
+	schema := &Schema{}
+	if err := json.NewDecoder(reader).Decode(schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// LoadFile reads an interaction model JSON file
+func LoadFile(path string) (*Schema, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Load(file)
+}
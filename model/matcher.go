@@ -0,0 +1,291 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/koblas/askgo/alexa"
+)
+
+// ErrNoMatch is returned when an utterance doesn't match any intent of the model
+var ErrNoMatch = errors.New("model: utterance does not match the interaction model")
+
+// Utterances used for built-in intents that are declared without samples
+var builtinSamples = map[string][]string{
+	"AMAZON.HelpIntent":             {"help", "help me", "what can i say", "what can i do"},
+	"AMAZON.StopIntent":             {"stop", "off", "shut up", "quit", "exit"},
+	"AMAZON.CancelIntent":           {"cancel", "never mind", "forget it"},
+	"AMAZON.PauseIntent":            {"pause", "pause that"},
+	"AMAZON.ResumeIntent":           {"resume", "continue", "keep going"},
+	"AMAZON.RepeatIntent":           {"repeat", "say that again", "repeat that"},
+	"AMAZON.StartOverIntent":        {"start over", "restart", "start again"},
+	"AMAZON.YesIntent":              {"yes", "yeah", "yep", "sure"},
+	"AMAZON.NoIntent":               {"no", "nope", "no thanks"},
+	"AMAZON.NextIntent":             {"next", "skip", "skip forward"},
+	"AMAZON.PreviousIntent":         {"previous", "go back", "skip back"},
+	"AMAZON.NavigateHomeIntent":     {"go home", "home"},
+	"AMAZON.MoreIntent":             {"more", "show more"},
+	"AMAZON.ScrollDownIntent":       {"scroll down"},
+	"AMAZON.ScrollUpIntent":         {"scroll up"},
+	"AMAZON.LoopOnIntent":           {"loop on", "loop"},
+	"AMAZON.LoopOffIntent":          {"loop off", "stop looping"},
+	"AMAZON.ShuffleOnIntent":        {"shuffle", "shuffle on"},
+	"AMAZON.ShuffleOffIntent":       {"shuffle off", "stop shuffling"},
+	"AMAZON.NavigateSettingsIntent": {"settings", "open settings"},
+}
+
+// Slot types that only capture digits
+var numericTypes = map[string]bool{
+	"AMAZON.NUMBER":            true,
+	"AMAZON.FOUR_DIGIT_NUMBER": true,
+}
+
+const fallbackIntent = "AMAZON.FallbackIntent"
+
+// Matcher resolves plain text utterances into intents using the samples of an interaction model.
+//
+// Matching is deterministic: an utterance must match a whole sample, slots of custom types only
+// match their values and synonyms, numeric slots only match digits and any other slot matches
+// any text.  When several samples match the one with the most literal text wins, then the one
+// with the most custom slots and then the one declared first.  If nothing matches and the model
+// has AMAZON.FallbackIntent it is returned.
+type Matcher struct {
+	// ApplicationID is used to build the entity resolution authority of custom slot types
+	ApplicationID string
+
+	schema   *Schema
+	patterns []*samplePattern
+}
+
+type samplePattern struct {
+	intent      *Intent
+	regex       *regexp.Regexp
+	slots       []string
+	literal     int
+	constrained int
+}
+
+// NewMatcher compiles the samples of the model
+func NewMatcher(schema *Schema) (*Matcher, error) {
+	matcher := &Matcher{schema: schema}
+
+	for i := range schema.InteractionModel.LanguageModel.Intents {
+		intent := &schema.InteractionModel.LanguageModel.Intents[i]
+
+		samples := intent.Samples
+		if len(samples) == 0 {
+			samples = builtinSamples[intent.Name]
+		}
+
+		for _, sample := range samples {
+			pattern, err := matcher.compile(intent, sample)
+			if err != nil {
+				return nil, err
+			}
+			matcher.patterns = append(matcher.patterns, pattern)
+		}
+	}
+
+	return matcher, nil
+}
+
+func (matcher *Matcher) compile(intent *Intent, sample string) (*samplePattern, error) {
+	pattern := &samplePattern{intent: intent}
+	parts := make([]string, 0)
+
+	for _, token := range splitSample(sample) {
+		if !strings.HasPrefix(token, "{") {
+			text := normalize(token)
+			if text == "" {
+				continue
+			}
+			pattern.literal += len(text)
+			parts = append(parts, regexp.QuoteMeta(text))
+			continue
+		}
+
+		name := strings.Trim(token, "{}")
+		var slot *Slot
+		for j := range intent.Slots {
+			if intent.Slots[j].Name == name {
+				slot = &intent.Slots[j]
+			}
+		}
+		if slot == nil {
+			return nil, fmt.Errorf("model: sample %q of %s references unknown slot %s", sample, intent.Name, name)
+		}
+
+		capture := "(.+?)"
+		if numericTypes[slot.Type] {
+			capture = `(\d+)`
+		} else if values := matcher.typeValues(slot.Type); len(values) != 0 {
+			quoted := make([]string, 0, len(values))
+			for _, value := range values {
+				quoted = append(quoted, regexp.QuoteMeta(value))
+			}
+			capture = "(" + strings.Join(quoted, "|") + ")"
+			pattern.constrained++
+		}
+
+		pattern.slots = append(pattern.slots, name)
+		parts = append(parts, capture)
+	}
+
+	regex, err := regexp.Compile("^" + strings.Join(parts, " ") + "$")
+	if err != nil {
+		return nil, err
+	}
+	pattern.regex = regex
+
+	return pattern, nil
+}
+
+// typeValues returns the normalized values and synonyms of a custom type, longest first
+func (matcher *Matcher) typeValues(typeName string) []string {
+	slotType := matcher.schema.FindType(typeName)
+	if slotType == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	values := make([]string, 0)
+	for _, value := range slotType.Values {
+		for _, text := range append([]string{value.Name.Value}, value.Name.Synonyms...) {
+			if text = normalize(text); text != "" && !seen[text] {
+				seen[text] = true
+				values = append(values, text)
+			}
+		}
+	}
+	sort.SliceStable(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	return values
+}
+
+// Match resolves the utterance into an intent with its slots filled in
+func (matcher *Matcher) Match(utterance string) (alexa.Intent, error) {
+	text := normalize(utterance)
+
+	var best *samplePattern
+	var bestValues []string
+
+	for _, pattern := range matcher.patterns {
+		found := pattern.regex.FindStringSubmatch(text)
+		if found == nil {
+			continue
+		}
+		if best == nil || pattern.literal > best.literal ||
+			(pattern.literal == best.literal && pattern.constrained > best.constrained) {
+			best = pattern
+			bestValues = found[1:]
+		}
+	}
+
+	if best == nil {
+		if intent := matcher.schema.FindIntent(fallbackIntent); intent != nil {
+			return matcher.buildIntent(intent, nil, nil), nil
+		}
+		return alexa.Intent{}, ErrNoMatch
+	}
+
+	return matcher.buildIntent(best.intent, best.slots, bestValues), nil
+}
+
+func (matcher *Matcher) buildIntent(intent *Intent, names []string, values []string) alexa.Intent {
+	result := alexa.Intent{
+		Name:               intent.Name,
+		ConfirmationStatus: "NONE",
+		Slots:              make(map[string]alexa.IntentSlot),
+	}
+
+	for _, slot := range intent.Slots {
+		result.Slots[slot.Name] = alexa.IntentSlot{Name: slot.Name, ConfirmationStatus: "NONE"}
+	}
+
+	for i, name := range names {
+		slot := result.Slots[name]
+		slot.Value = values[i]
+		slot.Source = "USER"
+
+		for _, declared := range intent.Slots {
+			if declared.Name != name {
+				continue
+			}
+			if resolutions := matcher.resolve(declared.Type, values[i]); resolutions != nil {
+				slot.Resolutions = resolutions
+			}
+		}
+		result.Slots[name] = slot
+	}
+
+	return result
+}
+
+// resolve performs entity resolution of a value against a custom slot type
+func (matcher *Matcher) resolve(typeName, value string) *alexa.Resolutions {
+	slotType := matcher.schema.FindType(typeName)
+	if slotType == nil {
+		return nil
+	}
+
+	resolution := alexa.Resolution{
+		Authority: "amzn1.er-authority.echo-sdk." + matcher.ApplicationID + "." + typeName,
+		Status:    alexa.ResolutionStatus{Code: "ER_SUCCESS_NO_MATCH"},
+	}
+
+	for _, candidate := range slotType.Values {
+		for _, text := range append([]string{candidate.Name.Value}, candidate.Name.Synonyms...) {
+			if normalize(text) == value {
+				resolution.Values = append(resolution.Values, alexa.ResolutionItem{
+					Value: alexa.ResolutionValue{Name: candidate.Name.Value, ID: candidate.ID},
+				})
+				break
+			}
+		}
+	}
+	if len(resolution.Values) != 0 {
+		resolution.Status.Code = "ER_SUCCESS_MATCH"
+	}
+
+	return &alexa.Resolutions{ResolutionsPerAuthority: []alexa.Resolution{resolution}}
+}
+
+// splitSample splits a sample into literal text and {Slot} references
+func splitSample(sample string) []string {
+	tokens := make([]string, 0)
+	for sample != "" {
+		start := strings.Index(sample, "{")
+		if start < 0 {
+			tokens = append(tokens, sample)
+			break
+		}
+		end := strings.Index(sample[start:], "}")
+		if end < 0 {
+			tokens = append(tokens, sample)
+			break
+		}
+		if start > 0 {
+			tokens = append(tokens, sample[:start])
+		}
+		tokens = append(tokens, sample[start:start+end+1])
+		sample = sample[start+end+1:]
+	}
+	return tokens
+}
+
+// normalize lower cases the text, drops punctuation and collapses whitespace
+func normalize(text string) string {
+	mapped := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '\'':
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, text)
+
+	return strings.Join(strings.Fields(mapped), " ")
+}
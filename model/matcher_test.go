@@ -0,0 +1,78 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/koblas/askgo/model"
+	"github.com/stretchr/testify/require"
+)
+
+const quizModel = `{
+  "interactionModel": {
+    "languageModel": {
+      "invocationName": "quiz game",
+      "intents": [
+        {"name": "AMAZON.HelpIntent", "samples": []},
+        {"name": "AMAZON.FallbackIntent", "samples": []},
+        {
+          "name": "AnswerIntent",
+          "samples": ["{StateName}", "{Abbreviation}", "what is the capital of {StateName}", "{StatehoodYear} information"],
+          "slots": [
+            {"name": "StateName", "type": "US_STATE"},
+            {"name": "Abbreviation", "type": "US_STATE_ABBR"},
+            {"name": "StatehoodYear", "type": "AMAZON.FOUR_DIGIT_NUMBER"}
+          ]
+        },
+        {"name": "QuizIntent", "samples": ["start a quiz", "quiz me"]}
+      ],
+      "types": [
+        {"name": "US_STATE", "values": [
+          {"id": "OH", "name": {"value": "Ohio", "synonyms": ["buckeye state"]}},
+          {"id": "NY", "name": {"value": "New York"}}
+        ]},
+        {"name": "US_STATE_ABBR", "values": [{"name": {"value": "OH"}}, {"name": {"value": "NY"}}]}
+      ]
+    }
+  }
+}`
+
+func Test_Matcher(t *testing.T) {
+	schema, err := model.Load(strings.NewReader(quizModel))
+	require.NoError(t, err)
+
+	matcher, err := model.NewMatcher(schema)
+	require.NoError(t, err)
+	matcher.ApplicationID = "amzn1.ask.skill.test"
+
+	intent, err := matcher.Match("What is the capital of the buckeye state")
+	require.NoError(t, err)
+	require.Equal(t, "AMAZON.FallbackIntent", intent.Name)
+
+	intent, err = matcher.Match("What is the capital of Buckeye State?")
+	require.NoError(t, err)
+	require.Equal(t, "AnswerIntent", intent.Name)
+	require.Len(t, intent.Slots, 3)
+	require.Equal(t, "buckeye state", intent.Slots["StateName"].Value)
+	require.Equal(t, "", intent.Slots["Abbreviation"].Value)
+
+	resolved := intent.Slots["StateName"].FirstResolvedValue()
+	require.NotNil(t, resolved)
+	require.Equal(t, "Ohio", resolved.Name)
+	require.Equal(t, "OH", resolved.ID)
+	require.Equal(t, "amzn1.er-authority.echo-sdk.amzn1.ask.skill.test.US_STATE",
+		intent.Slots["StateName"].GetResolutions().ResolutionsPerAuthority[0].Authority)
+
+	intent, err = matcher.Match("1803 information")
+	require.NoError(t, err)
+	require.Equal(t, "1803", intent.Slots["StatehoodYear"].Value)
+	require.Nil(t, intent.Slots["StatehoodYear"].Resolutions)
+
+	intent, err = matcher.Match("help")
+	require.NoError(t, err)
+	require.Equal(t, "AMAZON.HelpIntent", intent.Name)
+
+	intent, err = matcher.Match("quiz me")
+	require.NoError(t, err)
+	require.Equal(t, "QuizIntent", intent.Name)
+}
@@ -0,0 +1,168 @@
+package model_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/koblas/askgo/model"
+	"github.com/stretchr/testify/require"
+)
+
+var definition = &model.Definition{
+	InvocationName: map[string]string{"en-US": "quiz game", "de-DE": "quiz spiel"},
+	Intents: []model.IntentDefinition{
+		{Name: alexa.HelpIntent},
+		{
+			Name: "AnswerIntent",
+			Samples: model.Localized{
+				"en": {"tell me about {StateName}", "what is the capital of {StateName}"},
+				"de": {"erzähl mir von {StateName}"},
+			},
+			Slots: []model.SlotDefinition{
+				{
+					Name:                "StateName",
+					Type:                "US_STATE",
+					ElicitationRequired: true,
+					Elicitation:         model.Localized{"en": {"Which state?"}, "de": {"Welcher Staat?"}},
+				},
+			},
+		},
+	},
+	Types: []model.SlotTypeDefinition{
+		{
+			Name: "US_STATE",
+			Values: []model.SlotValueDefinition{
+				{ID: "OH", Value: map[string]string{"": "Ohio"}, Synonyms: model.Localized{"": {"buckeye state"}}},
+				{ID: "CA", Value: map[string]string{"": "California", "de-DE": "Kalifornien"}},
+			},
+		},
+	},
+}
+
+func Test_Schema(t *testing.T) {
+	require.Equal(t, []string{"de-DE", "en-US"}, definition.Locales())
+
+	schema, err := definition.Schema("de-DE")
+	require.NoError(t, err)
+
+	lm := schema.InteractionModel.LanguageModel
+	require.Equal(t, "quiz spiel", lm.InvocationName)
+	require.Equal(t, []string{}, lm.Intents[0].Samples)
+	require.Equal(t, []string{"erzähl mir von {StateName}"}, lm.Intents[1].Samples)
+	require.Equal(t, "Kalifornien", lm.Types[0].Values[1].Name.Value)
+	require.Equal(t, []string{"buckeye state"}, lm.Types[0].Values[0].Name.Synonyms)
+
+	require.NotNil(t, schema.InteractionModel.Dialog)
+	require.Equal(t, "Elicit.Slot.AnswerIntent.StateName", schema.InteractionModel.Dialog.Intents[0].Slots[0].Prompts["elicitation"])
+	require.Equal(t, "Welcher Staat?", schema.InteractionModel.Prompts[0].Variations[0].Value)
+
+	_, err = definition.Schema("fr-FR")
+	require.Error(t, err)
+
+	bad := &model.Definition{
+		InvocationName: map[string]string{"en-US": "bad"},
+		Intents:        []model.IntentDefinition{{Name: "X", Samples: model.Localized{"": {"go {Where}"}}}},
+	}
+	_, err = bad.Schema("en-US")
+	require.Error(t, err)
+}
+
+func Test_Check(t *testing.T) {
+	schema, err := definition.Schema("en-US")
+	require.NoError(t, err)
+
+	handler := func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+		return input.GetResponse(), nil
+	}
+
+	skill := &askgo.Skill{
+		Handlers: []askgo.RequestHandler{
+			&askgo.Route{Name: "answer", Intents: []string{"AnswerIntent", "QuizIntent"}, Handler: handler},
+		},
+	}
+
+	problems := model.Check(schema, skill)
+	require.Len(t, problems, 2)
+	require.Equal(t, model.Problem{Kind: model.UnknownIntent, Intent: "QuizIntent", Handler: "answer"}, problems[0])
+	require.Equal(t, model.Problem{Kind: model.UnhandledIntent, Intent: alexa.HelpIntent}, problems[1])
+}
+
+// failingLoader stands in for an interceptor loading attributes from a store
+type failingLoader struct {
+	calls *int
+}
+
+func (loader failingLoader) Process(input askgo.HandlerInput) error {
+	*loader.calls++
+	return errors.New("store unavailable")
+}
+
+func Test_CheckStateMachine(t *testing.T) {
+	schema, err := (&model.Definition{
+		InvocationName: map[string]string{"en-US": "quiz game"},
+		Intents: []model.IntentDefinition{
+			{Name: alexa.HelpIntent}, {Name: alexa.RepeatIntent}, {Name: alexa.StopIntent},
+			{Name: "QuizIntent", Samples: model.Localized{"": {"start a quiz"}}},
+			{Name: "AnswerIntent", Samples: model.Localized{"": {"the answer is {Answer}"}},
+				Slots: []model.SlotDefinition{{Name: "Answer", Type: "AMAZON.SearchQuery"}}},
+		},
+	}).Schema("en-US")
+	require.NoError(t, err)
+
+	handler := func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+		return input.GetResponse(), nil
+	}
+	calls := 0
+	skill := &askgo.Skill{
+		RequestInterceptors: []askgo.RequestInterceptor{failingLoader{&calls}},
+		Handlers: []askgo.RequestHandler{
+			&askgo.StateMachine{Initial: "START", States: []*askgo.State{
+				{Name: "START", Handlers: []askgo.RequestHandler{askgo.IntentRoute(handler, "QuizIntent")}, Unhandled: handler},
+				{Name: "QUIZ", Handlers: []askgo.RequestHandler{askgo.IntentRoute(handler, "AnswerIntent")}, Help: handler},
+			}},
+		},
+		FallbackHandler: handler,
+		Repeat:          &askgo.Repeat{},
+	}
+
+	// The Unhandled handlers and the fallback handler don't hide the missing stop handler,
+	// the interceptors are not run unless asked for
+	problems := model.Check(schema, skill)
+	require.Equal(t, []model.Problem{{Kind: model.UnhandledIntent, Intent: alexa.StopIntent}}, problems)
+	require.Equal(t, 0, calls)
+
+	problems = model.CheckWithOptions(schema, skill, model.CheckOptions{RequestInterceptors: true})
+	require.Len(t, problems, 4, "every intent but the repeat fails")
+	require.Equal(t, model.CheckFailed, problems[0].Kind)
+	require.Equal(t, "model_test.failingLoader", problems[0].Handler)
+	require.EqualError(t, problems[0].Err, "store unavailable")
+}
+
+func Test_CheckPanic(t *testing.T) {
+	schema, err := definition.Schema("en-US")
+	require.NoError(t, err)
+
+	skill := &askgo.Skill{
+		Handlers: []askgo.RequestHandler{
+			&askgo.Route{Name: "answer", Intents: []string{"AnswerIntent"},
+				Predicate: func(input askgo.HandlerInput) bool {
+					return input.GetRequest().Intent.Slots["StateName"].Value != ""
+				}},
+			&askgo.Route{Name: "help", Intents: []string{alexa.HelpIntent},
+				Predicate: func(input askgo.HandlerInput) bool {
+					return input.GetRequestEnvelope().Session.Attributes["score"].(int) > 0
+				}},
+		},
+	}
+
+	problems := model.Check(schema, skill)
+	require.Len(t, problems, 2)
+	require.Equal(t, model.Problem{Kind: model.UnhandledIntent, Intent: "AnswerIntent"}, problems[1])
+	require.Equal(t, model.CheckFailed, problems[0].Kind)
+	require.Equal(t, alexa.HelpIntent, problems[0].Intent)
+	require.Equal(t, "help", problems[0].Handler)
+	var panicErr *askgo.PanicError
+	require.True(t, errors.As(problems[0].Err, &panicErr))
+}
@@ -0,0 +1,114 @@
+// Package model describes the Alexa interaction model.
+//
+// The interaction model can be declared in Go as a Definition, next to the handlers
+// of the skill, and the interactionModel JSON generated for each locale.  Check reports
+// the differences between the model and the intents handled by a skill.
+package model
+
+// Schema is the interaction model document for a single locale, as uploaded to the developer console
+type Schema struct {
+	InteractionModel InteractionModel `json:"interactionModel"`
+}
+
+// InteractionModel contains the language model, dialog model and prompts
+type InteractionModel struct {
+	LanguageModel LanguageModel `json:"languageModel"`
+	Dialog        *Dialog       `json:"dialog,omitempty"`
+	Prompts       []Prompt      `json:"prompts,omitempty"`
+}
+
+// LanguageModel is the invocation name, intents and slot types of the skill
+type LanguageModel struct {
+	InvocationName string     `json:"invocationName"`
+	Intents        []Intent   `json:"intents"`
+	Types          []SlotType `json:"types,omitempty"`
+}
+
+// Intent is an intent with its sample utterances and slots
+type Intent struct {
+	Name    string   `json:"name"`
+	Samples []string `json:"samples"`
+	Slots   []Slot   `json:"slots,omitempty"`
+}
+
+// Slot is a slot of an intent
+type Slot struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Samples []string `json:"samples,omitempty"`
+}
+
+// SlotType is a custom slot type
+type SlotType struct {
+	Name   string          `json:"name"`
+	Values []SlotTypeValue `json:"values"`
+}
+
+// SlotTypeValue is one of the values of a custom slot type
+type SlotTypeValue struct {
+	ID   string            `json:"id,omitempty"`
+	Name SlotTypeValueName `json:"name"`
+}
+
+// SlotTypeValueName is the value and synonyms of a slot type value
+type SlotTypeValueName struct {
+	Value    string   `json:"value"`
+	Synonyms []string `json:"synonyms,omitempty"`
+}
+
+// Dialog is the dialog model of the skill
+type Dialog struct {
+	DelegationStrategy string         `json:"delegationStrategy,omitempty"`
+	Intents            []DialogIntent `json:"intents"`
+}
+
+// DialogIntent is the dialog model of an intent
+type DialogIntent struct {
+	Name                 string            `json:"name"`
+	DelegationStrategy   string            `json:"delegationStrategy,omitempty"`
+	ConfirmationRequired bool              `json:"confirmationRequired"`
+	Prompts              map[string]string `json:"prompts"`
+	Slots                []DialogSlot      `json:"slots"`
+}
+
+// DialogSlot is the dialog model of a slot
+type DialogSlot struct {
+	Name                 string            `json:"name"`
+	Type                 string            `json:"type"`
+	ElicitationRequired  bool              `json:"elicitationRequired"`
+	ConfirmationRequired bool              `json:"confirmationRequired"`
+	Prompts              map[string]string `json:"prompts"`
+}
+
+// Prompt is a prompt used by the dialog model
+type Prompt struct {
+	ID         string            `json:"id"`
+	Variations []PromptVariation `json:"variations"`
+}
+
+// PromptVariation is one of the ways a prompt can be spoken
+type PromptVariation struct {
+	// Type is PlainText or SSML
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// FindIntent returns the intent with the given name or nil
+func (schema *Schema) FindIntent(name string) *Intent {
+	for i := range schema.InteractionModel.LanguageModel.Intents {
+		if intent := &schema.InteractionModel.LanguageModel.Intents[i]; intent.Name == name {
+			return intent
+		}
+	}
+	return nil
+}
+
+// FindType returns the custom slot type with the given name or nil
+func (schema *Schema) FindType(name string) *SlotType {
+	for i := range schema.InteractionModel.LanguageModel.Types {
+		if slotType := &schema.InteractionModel.LanguageModel.Types[i]; slotType.Name == name {
+			return slotType
+		}
+	}
+	return nil
+}
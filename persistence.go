@@ -0,0 +1,194 @@
+package askgo
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// PersistenceAdapter stores the persistent attributes of the skill (e.g. in DynamoDB), which
+// unlike the session attributes outlive the session
+type PersistenceAdapter interface {
+	GetAttributes(ctx context.Context, key string) (map[string]interface{}, error)
+	SaveAttributes(ctx context.Context, key string, attributes map[string]interface{}) error
+}
+
+// PartitionKeyFunc returns the key of the persistent attributes of a request
+type PartitionKeyFunc func(envelope RequestEnvelope) (string, error)
+
+// ErrNoPartitionKey is returned when the request has nothing to key the persistent attributes by
+var ErrNoPartitionKey = errors.New("askgo: the request has no persistence partition key")
+
+// ErrNoPersistence is returned when the persistent attributes are used without Skill.Persistence
+var ErrNoPersistence = errors.New("askgo: the skill has no persistence adapter")
+
+// UserPartitionKey keys the persistent attributes by the user ID of the request
+func UserPartitionKey(envelope RequestEnvelope) (string, error) {
+	if id := envelope.Context.System.User.UserID; id != "" {
+		return id, nil
+	}
+	if id := envelope.Session.User.UserID; id != "" {
+		return id, nil
+	}
+	return "", ErrNoPartitionKey
+}
+
+// PersonPartitionKey keys the persistent attributes by the person recognized by voice, or by
+// the user ID when the speaker is not recognized
+func PersonPartitionKey(envelope RequestEnvelope) (string, error) {
+	if person := envelope.Context.System.Person; person != nil && person.PersonID != "" {
+		return person.PersonID, nil
+	}
+	return UserPartitionKey(envelope)
+}
+
+// DevicePartitionKey keys the persistent attributes by the device ID of the request
+func DevicePartitionKey(envelope RequestEnvelope) (string, error) {
+	if id := envelope.Context.System.Device.DeviceID; id != "" {
+		return id, nil
+	}
+	return "", ErrNoPartitionKey
+}
+
+// Persistence configures the persistent attributes of a skill, handlers use
+// GetPersistentAttributes, SetPersistentAttributes and SavePersistentAttributes.
+type Persistence struct {
+	Adapter PersistenceAdapter
+	// PartitionKey defaults to UserPartitionKey
+	PartitionKey PartitionKeyFunc
+}
+
+// persistentAttributes are the persistent attributes of a request, loaded when first used
+type persistentAttributes struct {
+	persistence *Persistence
+	envelope    RequestEnvelope
+
+	mutex      sync.Mutex
+	loaded     bool
+	attributes map[string]interface{}
+}
+
+type persistenceKey struct{}
+
+func withPersistence(ctx context.Context, persistence *Persistence, envelope RequestEnvelope) context.Context {
+	return context.WithValue(ctx, persistenceKey{}, &persistentAttributes{persistence: persistence, envelope: envelope})
+}
+
+func persistentFromInput(input HandlerInput) (*persistentAttributes, error) {
+	if attributes, ok := input.GetContext().Value(persistenceKey{}).(*persistentAttributes); ok {
+		return attributes, nil
+	}
+	return nil, ErrNoPersistence
+}
+
+func (p *persistentAttributes) key() (string, error) {
+	partitionKey := p.persistence.PartitionKey
+	if partitionKey == nil {
+		partitionKey = UserPartitionKey
+	}
+	return partitionKey(p.envelope)
+}
+
+// GetPersistentAttributes returns the persistent attributes of the request, they are loaded
+// from the adapter the first time
+func GetPersistentAttributes(input HandlerInput) (map[string]interface{}, error) {
+	p, err := persistentFromInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if !p.loaded {
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		attributes, err := p.persistence.Adapter.GetAttributes(input.GetContext(), key)
+		if err != nil {
+			return nil, err
+		}
+		if attributes == nil {
+			attributes = make(map[string]interface{})
+		}
+		p.attributes = attributes
+		p.loaded = true
+	}
+	return p.attributes, nil
+}
+
+// SetPersistentAttributes replaces the persistent attributes of the request, they are only
+// stored by SavePersistentAttributes
+func SetPersistentAttributes(input HandlerInput, attributes map[string]interface{}) error {
+	p, err := persistentFromInput(input)
+	if err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.attributes = attributes
+	p.loaded = true
+	return nil
+}
+
+// SavePersistentAttributes stores the persistent attributes of the request with the adapter
+func SavePersistentAttributes(input HandlerInput) error {
+	p, err := persistentFromInput(input)
+	if err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if !p.loaded {
+		return nil
+	}
+	key, err := p.key()
+	if err != nil {
+		return err
+	}
+	return p.persistence.Adapter.SaveAttributes(input.GetContext(), key, p.attributes)
+}
+
+// MemoryPersistence is a PersistenceAdapter keeping the attributes in memory, for tests
+type MemoryPersistence struct {
+	mutex      sync.Mutex
+	attributes map[string]map[string]interface{}
+}
+
+var _ PersistenceAdapter = &MemoryPersistence{}
+
+// GetAttributes returns the attributes stored for the key, or nil
+func (m *MemoryPersistence) GetAttributes(ctx context.Context, key string) (map[string]interface{}, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return copyAttributes(m.attributes[key]), nil
+}
+
+// SaveAttributes stores the attributes for the key
+func (m *MemoryPersistence) SaveAttributes(ctx context.Context, key string, attributes map[string]interface{}) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.attributes == nil {
+		m.attributes = make(map[string]map[string]interface{})
+	}
+	m.attributes[key] = copyAttributes(attributes)
+	return nil
+}
+
+func copyAttributes(attributes map[string]interface{}) map[string]interface{} {
+	if attributes == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(attributes))
+	for key, value := range attributes {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package askgo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/askgotest"
+	"github.com/stretchr/testify/require"
+)
+
+func countVisits(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+	attributes, err := askgo.GetPersistentAttributes(input)
+	if err != nil {
+		return nil, err
+	}
+	visits, _ := attributes["visits"].(int)
+	attributes["visits"] = visits + 1
+	if err := askgo.SavePersistentAttributes(input); err != nil {
+		return nil, err
+	}
+	return input.GetResponse().Speak("Welcome"), nil
+}
+
+func Test_Persistence(t *testing.T) {
+	adapter := &askgo.MemoryPersistence{}
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		Persistence:     &askgo.Persistence{Adapter: adapter, PartitionKey: askgo.DevicePartitionKey},
+		Handlers:        []askgo.RequestHandler{askgo.RequestRoute(countVisits, "LaunchRequest")},
+	}
+	conv := askgotest.NewConversation(skill)
+
+	for idx := 0; idx < 2; idx++ {
+		conv.Reset()
+		_, err := conv.Launch()
+		require.NoError(t, err)
+	}
+
+	attributes, err := adapter.GetAttributes(context.Background(), conv.DeviceID)
+	require.NoError(t, err)
+	require.Equal(t, 2, attributes["visits"])
+
+	// Without Skill.Persistence the handler gets ErrNoPersistence
+	skill.Persistence = nil
+	_, err = skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), askgotest.NewLaunchRequest()))
+	require.True(t, errors.Is(err, askgo.ErrNoPersistence))
+}
@@ -0,0 +1,32 @@
+package askgo
+
+import (
+	"github.com/koblas/askgo/alexa"
+)
+
+// RecognizedPerson returns the speaker recognized by their voice profile, or nil
+func RecognizedPerson(input HandlerInput) *alexa.Person {
+	person := input.GetRequestEnvelope().Context.System.Person
+	if person == nil || person.PersonID == "" {
+		return nil
+	}
+	return person
+}
+
+// PersonNameSSML has Alexa say the first name of a recognized person, unlike the customer
+// profile API it does not need a permission
+//
+//	input.GetResponse().Speak("Welcome back " + askgo.PersonNameSSML(person.PersonID))
+func PersonNameSSML(personID string) string {
+	return `<alexa:name type="first" personId="` + personID + `"/>`
+}
+
+// GivenName returns the given name of the recognized person, or of the account holder when the
+// speaker is not recognized, using the customer profile API
+func GivenName(input HandlerInput) (string, error) {
+	client := NewServiceClient(input)
+	if RecognizedPerson(input) != nil {
+		return client.GetPersonGivenName(input.GetContext())
+	}
+	return client.GetAccountGivenName(input.GetContext())
+}
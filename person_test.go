@@ -0,0 +1,96 @@
+package askgo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/askgotest"
+	"github.com/stretchr/testify/require"
+)
+
+func greet(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+	name, err := askgo.GivenName(input)
+	if err != nil {
+		return nil, err
+	}
+	if person := askgo.RecognizedPerson(input); person != nil {
+		return input.GetResponse().Speak("Welcome back " + askgo.PersonNameSSML(person.PersonID) + ", or " + name), nil
+	}
+	return input.GetResponse().Speak("Welcome " + name), nil
+}
+
+func Test_Person(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/persons/~current/profile/givenName":
+			w.Write([]byte(`"Grace"`))
+		case "/v2/accounts/~current/settings/Profile.givenName":
+			w.Write([]byte(`"Ada"`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	adapter := &askgo.MemoryPersistence{}
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		Persistence:     &askgo.Persistence{Adapter: adapter, PartitionKey: askgo.PersonPartitionKey},
+		Handlers: []askgo.RequestHandler{askgo.RequestRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+			if _, err := countVisits(input); err != nil {
+				return nil, err
+			}
+			return greet(input)
+		}, "LaunchRequest")},
+	}
+	conv := askgotest.NewConversation(skill)
+	conv.APIEndpoint = server.URL
+
+	response, err := conv.Launch()
+	require.NoError(t, err)
+	require.Equal(t, "Welcome Ada", askgotest.Speech(response))
+
+	conv.Reset()
+	conv.PersonID = "amzn1.ask.person.1"
+	response, err = conv.Launch()
+	require.NoError(t, err)
+	require.Contains(t, response.Response.OutputSpeech.SSML, `<alexa:name type="first" personId="amzn1.ask.person.1"/>, or Grace`)
+
+	// The recognized speaker has their own persistent attributes
+	user, err := adapter.GetAttributes(context.Background(), conv.UserID)
+	require.NoError(t, err)
+	require.Equal(t, 1, user["visits"])
+	person, err := adapter.GetAttributes(context.Background(), "amzn1.ask.person.1")
+	require.NoError(t, err)
+	require.Equal(t, 1, person["visits"])
+}
+
+func Test_PersonAccessToken(t *testing.T) {
+	linker := &askgo.AccountLinker{Resolve: func(ctx context.Context, token string) (interface{}, error) {
+		return "identity of " + token, nil
+	}}
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		Handlers: []askgo.RequestHandler{&askgo.Route{
+			Intents:    []string{"OrderIntent"},
+			Middleware: []askgo.Middleware{linker.Middleware()},
+			Handler: func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				return input.GetResponse().Speak(askgo.LinkedIdentity(input).(string)), nil
+			},
+		}},
+	}
+	conv := askgotest.NewConversation(skill)
+	conv.AccessToken = "holder-token"
+
+	conv.Run(t, askgotest.Script{{Intent: "OrderIntent", Expect: askgotest.Expect{Speech: "identity of holder-token"}}})
+
+	// A recognized speaker without their own linked account uses the account holder's token
+	conv.PersonID = "amzn1.ask.person.1"
+	conv.Run(t, askgotest.Script{{Intent: "OrderIntent", Expect: askgotest.Expect{Speech: "identity of holder-token"}}})
+
+	conv.PersonAccessToken = "person-token"
+	conv.Run(t, askgotest.Script{{Intent: "OrderIntent", Expect: askgotest.Expect{Speech: "identity of person-token"}}})
+}
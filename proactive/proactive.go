@@ -0,0 +1,155 @@
+// Package proactive sends notifications to the users of a skill outside of a session with the
+// Proactive Events API.
+//
+//	client := &proactive.Client{LWA: &lwa.Client{ClientID: id, ClientSecret: secret}}
+//	event := proactive.NewEvent(proactive.MediaContentAvailable(proactive.MediaContent{
+//		ContentName: proactive.LocalizedAttribute("contentName"), ContentType: "EPISODE",
+//		ProviderName: proactive.LocalizedAttribute("providerName"), StartTime: start, Method: "STREAM",
+//	}), proactive.Multicast())
+//	event.Localize("en-US", map[string]string{"contentName": "Weekly quiz", "providerName": "Quiz Master"})
+//	err := client.Send(ctx, event)
+package proactive
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/lwa"
+)
+
+// Scope is the Login with Amazon scope of the Proactive Events API
+const Scope = "alexa::proactive_events"
+
+// API endpoints by region
+const (
+	NorthAmerica = "https://api.amazonalexa.com"
+	Europe       = "https://api.eu.amazonalexa.com"
+	FarEast      = "https://api.fe.amazonalexa.com"
+)
+
+// Paths of the live and development stages
+const (
+	LivePath        = "/v1/proactiveEvents"
+	DevelopmentPath = "/v1/proactiveEvents/stages/development"
+)
+
+// DefaultExpiry is how long new events are kept, at most 24 hours are allowed
+var DefaultExpiry = 23 * time.Hour
+
+// Client sends events to the Proactive Events API
+type Client struct {
+	// LWA provides the access tokens of the skill
+	LWA *lwa.Client
+	// Endpoint is the API endpoint of the region, defaults to NorthAmerica
+	Endpoint string
+	// Live sends the events to the users of the published skill rather than the development stage
+	Live bool
+	// HTTPClient defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// Event is a notification of a standard schema for an audience
+type Event struct {
+	Timestamp           time.Time             `json:"timestamp"`
+	ReferenceID         string                `json:"referenceId"`
+	ExpiryTime          time.Time             `json:"expiryTime"`
+	Event               Schema                `json:"event"`
+	LocalizedAttributes []LocalizedAttributes `json:"localizedAttributes"`
+	RelevantAudience    Audience              `json:"relevantAudience"`
+}
+
+// Schema is the name of the event schema (e.g. "AMAZON.MessageAlert.Activated") and its payload
+type Schema struct {
+	Name    string      `json:"name"`
+	Payload interface{} `json:"payload"`
+}
+
+// LocalizedAttributes are the values of the localized attributes of the payload for a locale,
+// the "locale" key holds the locale
+type LocalizedAttributes map[string]string
+
+// Audience is who receives the event
+type Audience struct {
+	// Type is "Unicast" or "Multicast"
+	Type    string          `json:"type"`
+	Payload AudiencePayload `json:"payload"`
+}
+
+// AudiencePayload identifies the user of a unicast event
+type AudiencePayload struct {
+	User string `json:"user,omitempty"`
+}
+
+// Unicast sends the event to a single user (the user ID of the requests of the skill)
+func Unicast(userID string) Audience {
+	return Audience{Type: "Unicast", Payload: AudiencePayload{User: userID}}
+}
+
+// Multicast sends the event to every user subscribed to the events of the skill
+func Multicast() Audience {
+	return Audience{Type: "Multicast"}
+}
+
+// LocalizedAttribute refers to the localized attribute with the name from the payload
+func LocalizedAttribute(name string) string {
+	return "localizedattribute:" + name
+}
+
+// NewEvent returns an event with a new reference ID, valid for DefaultExpiry
+func NewEvent(schema Schema, audience Audience) *Event {
+	now := time.Now().UTC()
+	return &Event{
+		Timestamp:           now,
+		ReferenceID:         newReferenceID(),
+		ExpiryTime:          now.Add(DefaultExpiry),
+		Event:               schema,
+		LocalizedAttributes: []LocalizedAttributes{},
+		RelevantAudience:    audience,
+	}
+}
+
+// Localize sets the values of the localized attributes for the locale
+func (event *Event) Localize(locale string, attributes map[string]string) *Event {
+	localized := LocalizedAttributes{"locale": locale}
+	for name, value := range attributes {
+		localized[name] = value
+	}
+	event.LocalizedAttributes = append(event.LocalizedAttributes, localized)
+	return event
+}
+
+func newReferenceID() string {
+	data := make([]byte, 16)
+	rand.Read(data)
+	return hex.EncodeToString(data)
+}
+
+// Send posts the event to the live or development stage
+func (client *Client) Send(ctx context.Context, event *Event) error {
+	token, err := client.LWA.Token(ctx, Scope)
+	if err != nil {
+		return err
+	}
+
+	endpoint := client.Endpoint
+	if endpoint == "" {
+		endpoint = NorthAmerica
+	}
+	path := DevelopmentPath
+	if client.Live {
+		path = LivePath
+	}
+
+	service := &askgo.ServiceClient{Endpoint: endpoint, Token: token, HTTPClient: client.HTTPClient}
+	err = service.Do(ctx, "POST", path, event, nil)
+	var serviceErr *askgo.ServiceError
+	if errors.As(err, &serviceErr) && serviceErr.StatusCode == http.StatusUnauthorized {
+		client.LWA.Invalidate(Scope)
+	}
+	return err
+}
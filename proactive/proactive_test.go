@@ -0,0 +1,101 @@
+package proactive_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/koblas/askgo/lwa"
+	"github.com/koblas/askgo/proactive"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send(t *testing.T) {
+	// The servers only record the requests, they are checked by the test
+	var mutex sync.Mutex
+	forms := []url.Values{}
+	auth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		mutex.Lock()
+		forms = append(forms, r.PostForm)
+		mutex.Unlock()
+		if r.PostForm.Get("client_secret") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "invalid_client", "error_description": "Client authentication failed"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token": "Atc|token", "expires_in": 3600, "token_type": "bearer"}`))
+	}))
+	defer auth.Close()
+
+	paths := []string{}
+	authorizations := []string{}
+	var body map[string]interface{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		paths = append(paths, r.URL.Path)
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer api.Close()
+
+	client := &proactive.Client{
+		LWA:      &lwa.Client{ClientID: "id", ClientSecret: "secret", TokenURL: auth.URL},
+		Endpoint: api.URL,
+	}
+
+	event := proactive.NewEvent(proactive.MessageAlertActivated(proactive.MessageAlert{
+		State:        proactive.MessageAlertState{Status: "UNREAD", Freshness: "NEW"},
+		MessageGroup: proactive.MessageGroup{Creator: proactive.Creator{Name: proactive.LocalizedAttribute("creator")}, Count: 1},
+	}), proactive.Unicast("amzn1.ask.account.1")).Localize("en-US", map[string]string{"creator": "Quiz Master"})
+	require.NoError(t, client.Send(context.Background(), event))
+
+	client.Live = true
+	require.NoError(t, client.Send(context.Background(), proactive.NewEvent(proactive.OccasionUpdated(proactive.Occasion{
+		State: proactive.OccasionState{ConfirmationStatus: "CONFIRMED"},
+		Occasion: proactive.OccasionDetail{OccasionType: "APPOINTMENT", Subject: "Quiz night",
+			Provider: proactive.Provider{Name: "Quiz Master"}, BookingTime: time.Now()},
+	}), proactive.Multicast())))
+
+	mutex.Lock()
+	require.Len(t, forms, 1, "the token is cached")
+	require.Equal(t, "client_credentials", forms[0].Get("grant_type"))
+	require.Equal(t, proactive.Scope, forms[0].Get("scope"))
+	require.Equal(t, []string{"Bearer Atc|token", "Bearer Atc|token"}, authorizations)
+	require.Equal(t, []string{proactive.DevelopmentPath, proactive.LivePath}, paths)
+	require.Equal(t, "AMAZON.Occasion.Updated", body["event"].(map[string]interface{})["name"])
+	require.Equal(t, map[string]interface{}{"type": "Multicast", "payload": map[string]interface{}{}}, body["relevantAudience"])
+	mutex.Unlock()
+
+	client.LWA = &lwa.Client{ClientID: "id", ClientSecret: "wrong", TokenURL: auth.URL}
+	err := client.Send(context.Background(), event)
+	var lwaErr *lwa.Error
+	require.True(t, errors.As(err, &lwaErr))
+	require.Equal(t, "invalid_client", lwaErr.Code)
+}
+
+func Test_EventJSON(t *testing.T) {
+	event := proactive.NewEvent(proactive.WeatherAlertActivated(proactive.WeatherAlert{
+		Source: proactive.LocalizedAttribute("source"), AlertType: "SNOW_STORM",
+	}), proactive.Multicast()).Localize("en-US", map[string]string{"source": "Weather service"})
+
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, map[string]interface{}{"weatherAlert": map[string]interface{}{
+		"source": "localizedattribute:source", "alertType": "SNOW_STORM",
+	}}, decoded["event"].(map[string]interface{})["payload"])
+	require.Equal(t, []interface{}{map[string]interface{}{"locale": "en-US", "source": "Weather service"}}, decoded["localizedAttributes"])
+	require.Len(t, decoded["referenceId"], 32)
+}
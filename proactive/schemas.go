@@ -0,0 +1,134 @@
+package proactive
+
+import "time"
+
+// MessageAlert is the payload of AMAZON.MessageAlert.Activated
+type MessageAlert struct {
+	State        MessageAlertState `json:"state"`
+	MessageGroup MessageGroup      `json:"messageGroup"`
+}
+
+// MessageAlertState has a Status of "UNREAD" or "FLAGGED" and a Freshness of "NEW" or "OVERDUE"
+type MessageAlertState struct {
+	Status    string `json:"status"`
+	Freshness string `json:"freshness,omitempty"`
+}
+
+// MessageGroup describes the messages, Urgency is "URGENT" or empty
+type MessageGroup struct {
+	Creator Creator `json:"creator"`
+	Count   int     `json:"count"`
+	Urgency string  `json:"urgency,omitempty"`
+}
+
+// Creator is the sender of the messages
+type Creator struct {
+	Name string `json:"name"`
+}
+
+// MessageAlertActivated tells the user about new messages
+func MessageAlertActivated(alert MessageAlert) Schema {
+	return Schema{Name: "AMAZON.MessageAlert.Activated", Payload: alert}
+}
+
+// OrderStatus is the payload of AMAZON.OrderStatus.Updated
+type OrderStatus struct {
+	State OrderState `json:"state"`
+	Order Order      `json:"order"`
+}
+
+// OrderState has a Status such as "ORDER_RECEIVED", "ORDER_SHIPPED", "ORDER_OUT_FOR_DELIVERY",
+// "PREORDER_RECEIVED" or "ORDER_DELIVERED"
+type OrderState struct {
+	Status          string           `json:"status"`
+	EnterTimestamp  *time.Time       `json:"enterTimestamp,omitempty"`
+	DeliveryDetails *DeliveryDetails `json:"deliveryDetails,omitempty"`
+}
+
+// DeliveryDetails gives the expected arrival of a shipped order
+type DeliveryDetails struct {
+	ExpectedArrival time.Time `json:"expectedArrival"`
+}
+
+// Order identifies the seller of the order
+type Order struct {
+	Seller Seller `json:"seller"`
+}
+
+// Seller of an order, the name is usually a LocalizedAttribute
+type Seller struct {
+	Name string `json:"name"`
+}
+
+// OrderStatusUpdated tells the user about the progress of an order
+func OrderStatusUpdated(status OrderStatus) Schema {
+	return Schema{Name: "AMAZON.OrderStatus.Updated", Payload: status}
+}
+
+// Occasion is the payload of AMAZON.Occasion.Updated
+type Occasion struct {
+	State    OccasionState  `json:"state"`
+	Occasion OccasionDetail `json:"occasion"`
+}
+
+// OccasionState has a ConfirmationStatus of "CONFIRMED", "CANCELED", "RESCHEDULED", "REQUESTED" or "CREATED"
+type OccasionState struct {
+	ConfirmationStatus string `json:"confirmationStatus"`
+}
+
+// OccasionDetail describes a reservation or appointment, OccasionType is e.g. "RESERVATION_REQUEST",
+// "RESERVATION", "APPOINTMENT_REQUEST" or "APPOINTMENT"
+type OccasionDetail struct {
+	OccasionType string    `json:"occasionType"`
+	Subject      string    `json:"subject"`
+	Provider     Provider  `json:"provider"`
+	BookingTime  time.Time `json:"bookingTime"`
+	Broker       *Provider `json:"broker,omitempty"`
+}
+
+// Provider is a business providing the content or occasion
+type Provider struct {
+	Name string `json:"name"`
+}
+
+// OccasionUpdated tells the user about a reservation or appointment
+func OccasionUpdated(occasion Occasion) Schema {
+	return Schema{Name: "AMAZON.Occasion.Updated", Payload: occasion}
+}
+
+// WeatherAlert is the payload of AMAZON.WeatherAlert.Activated, AlertType is e.g. "TORNADO",
+// "HURRICANE", "SNOW_STORM" or "THUNDER_STORM"
+type WeatherAlert struct {
+	Source    string `json:"source"`
+	AlertType string `json:"alertType"`
+}
+
+// WeatherAlertActivated tells the user about a weather alert
+func WeatherAlertActivated(alert WeatherAlert) Schema {
+	return Schema{Name: "AMAZON.WeatherAlert.Activated", Payload: map[string]interface{}{"weatherAlert": alert}}
+}
+
+// MediaContent describes the availability of content, ContentType is e.g. "BOOK", "EPISODE",
+// "ALBUM", "SINGLE", "MOVIE" or "GAME" and Method "STREAM", "AIR", "RELEASE", "PREMIERE" or "DROP"
+type MediaContent struct {
+	ContentName  string
+	ContentType  string
+	ProviderName string
+	StartTime    time.Time
+	Method       string
+}
+
+// MediaContentAvailable tells the user about upcoming content
+func MediaContentAvailable(content MediaContent) Schema {
+	return Schema{Name: "AMAZON.MediaContent.Available", Payload: map[string]interface{}{
+		"availability": map[string]interface{}{
+			"startTime": content.StartTime,
+			"provider":  Provider{Name: content.ProviderName},
+			"method":    content.Method,
+		},
+		"content": map[string]interface{}{
+			"name":        content.ContentName,
+			"contentType": content.ContentType,
+		},
+	}}
+}
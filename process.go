@@ -3,12 +3,14 @@ package askgo
 import (
 	"context"
 	"errors"
-	"log"
 	"math"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/koblas/askgo/alexa"
+	"github.com/koblas/askgo/i18n"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // RequestEnvelope is really alexa.RequestEnvelope
@@ -35,9 +37,23 @@ type Skill struct {
 	// Request handlers are responsible for handling one or more types of incoming requests.
 	Handlers []RequestHandler
 
+	// Middleware wraps the request handler, the first is the outermost.  The request and
+	// response interceptors run outside of the middleware.
+	Middleware []Middleware
+
+	// FallbackHandler handles the requests no request handler can handle, when it is nil
+	// a NoHandlerError is passed to the error handlers instead.
+	FallbackHandler HandlerFunc
+
+	// Repeat, when set, remembers the last response in the session attributes and handles
+	// AMAZON.RepeatIntent before the request handlers, which would otherwise take it with a
+	// catch-all route or an Unhandled state.
+	Repeat *Repeat
+
 	// Response interceptors are invoked immediately after execution of the request handler.
 	// Because response interceptors have access to the output generated from execution of the
 	// request handler, they are ideal for tasks such as response sanitization and validation.
+	// They are never given a nil response, a handler returning nil gets an empty one.
 	ResponseInterceptors []ResponseInterceptor
 
 	// ErrorHandlers are similar to request handlers, but
@@ -45,6 +61,43 @@ type Skill struct {
 	// They are invoked by the SDK when an error is returned during the
 	// course of request processing.
 	ErrorHandlers []ErrorHandler
+
+	// Observers are told about every verified request, including those that fail, time out
+	// or are answered by an error handler (e.g. to record them).
+	Observers []RequestObserver
+
+	// Persistence stores attributes beyond the session, keyed by user by default.
+	Persistence *Persistence
+
+	// Messages are the localized message catalogs for the skill, when set handlers can
+	// use HandlerInput.T to look up messages in the locale of the request.
+	Messages *i18n.Bundle
+
+	// Logger receives the log entries of the skill, the default uses slog.Default().
+	// Every request gets a logger with the request ID, session ID, request type,
+	// intent and locale attached, which handlers reach through HandlerInput.GetLogger.
+	Logger Logger
+
+	// Metrics receives the counters and durations of request processing, labelled by
+	// request type, intent, handler name and outcome.
+	Metrics Metrics
+
+	// Timeout limits the time taken to process a request (e.g. 6 * time.Second, as Alexa
+	// waits about 8 seconds), when the deadline of the context passed by Lambda is shorter it
+	// is used instead, and without a Timeout the Lambda deadline alone applies.  Handlers get
+	// a context with the deadline, on expiry a TimeoutError is passed to the error handlers
+	// (see TimeoutHandler) with a copy of the request while the handler is left running.
+	Timeout time.Duration
+
+	// ProgressiveSpeech is sent as a progressive response when a launch or intent request
+	// has not been answered after ProgressiveDelay, letting the user know the skill is working.
+	ProgressiveSpeech string
+	ProgressiveDelay  time.Duration
+
+	// TracerProvider creates the OpenTelemetry spans of each request, interceptor, handler and
+	// Alexa service call, the default is the global provider.  Handlers can add their own spans
+	// using the context of the HandlerInput.
+	TracerProvider trace.TracerProvider
 }
 
 // HandlerInput is the standard type for input for request handlers,
@@ -67,6 +120,12 @@ type HandlerInput interface {
 
 	// Update the running context object
 	SetContext(ctx context.Context)
+
+	// T returns the message for the key localized to the locale of the request
+	T(key string, args ...interface{}) string
+
+	// GetLogger returns the logger for the request
+	GetLogger() Logger
 }
 
 // RequestInterceptor are invoked immediately prior to execution of the request handler
@@ -112,60 +171,383 @@ type ErrorHandler interface {
 	Handle(input HandlerInput, e error) (*ResponseEnvelope, error)
 }
 
+// RequestObserver is told about every verified request however it ends: answered by a handler
+// or an error handler, failed or timed out
+type RequestObserver interface {
+	// Observe is called before the interceptors, the returned function is called once the
+	// request is over with its response and error, the error being kept when an error handler
+	// answered it
+	Observe(input HandlerInput) func(response *ResponseEnvelope, err error)
+}
+
 // ProcessRequest Main entry point for request processing
 func (skill *Skill) ProcessRequest(input HandlerInput) (interface{}, error) {
 	envelope := input.GetRequestEnvelope()
+	scope, logger := skill.start(input)
 
 	if skill.ApplicationID != "" {
 		if err := skill.verifyApplicationID(envelope); err != nil {
-			return nil, err
+			return skill.reject(input, scope, &VerificationError{Err: err})
 		}
 	} else {
-		log.Println("Ignoring application verification.")
+		logger.Debug("Ignoring application verification.")
 	}
 	if !skill.IgnoreTimestamp {
 		if err := skill.verifyTimestamp(envelope); err != nil {
-			return nil, err
+			return skill.reject(input, scope, &VerificationError{Err: err})
 		}
 	} else {
-		log.Println("Ignoring timestamp verification.")
+		logger.Debug("Ignoring timestamp verification.")
+	}
+
+	if skill.Messages != nil {
+		localizer := skill.Messages.Localizer(envelope.Request.Locale)
+		input.SetContext(i18n.WithLocalizer(input.GetContext(), localizer))
+	}
+
+	if skill.Persistence != nil {
+		input.SetContext(withPersistence(input.GetContext(), skill.Persistence, envelope))
+	}
+
+	if skill.ProgressiveDelay > 0 && skill.ProgressiveSpeech != "" {
+		defer skill.startProgressiveResponse(input)()
+	}
+
+	finish := make([]func(*ResponseEnvelope, error), 0, len(skill.Observers))
+	for _, observer := range skill.Observers {
+		finish = append(finish, observer.Observe(input))
+	}
+
+	var result interface{}
+	var err error
+	if skill.hasDeadline(input) {
+		result, err = skill.processWithTimeout(input, scope)
+	} else {
+		result, err = skill.process(input, scope)
 	}
 
-	for _, interceptor := range skill.RequestInterceptors {
-		if err := interceptor.Process(input); err != nil {
-			return skill.dispatchError(input, err)
+	if len(finish) != 0 {
+		response, _ := result.(*ResponseEnvelope)
+		cause := scope.cause()
+		if cause == nil {
+			cause = err
+		}
+		for _, fn := range finish {
+			fn(response, cause)
 		}
 	}
+	return result, err
+}
+
+// process runs the middleware chain for a verified request: the request interceptors,
+// the response interceptors, the middleware of the skill and finally the request handler
+func (skill *Skill) process(input HandlerInput, scope *requestScope) (interface{}, error) {
+	outcome := OutcomeSuccess
+
+	ctx, states := withNextStates(input.GetContext())
+	input.SetContext(ctx)
+
+	middleware := make([]Middleware, 0, len(skill.Middleware)+2)
+	middleware = append(middleware, skill.requestInterceptors(scope), skill.responseInterceptors(scope))
+	middleware = append(middleware, skill.Middleware...)
+	chain := Chain(func(input HandlerInput) (*ResponseEnvelope, error) {
+		return skill.handle(input, scope, &outcome)
+	}, middleware...)
 
 	var response *ResponseEnvelope
+	err := Capture(func() (err error) {
+		response, err = chain(input)
+		return err
+	})
+	if !scope.finish(input.GetContext()) {
+		input.GetLogger().Debug("Discarding the result of a timed out request", "error", err)
+		return response, err
+	}
+	if err != nil {
+		return skill.dispatchError(input, scope, err)
+	}
 
-	for _, handler := range skill.Handlers {
-		if handler.CanHandle(input) {
-			var err error
-			response, err = handler.Handle(input)
+	states.apply(response)
+	skill.Repeat.remember(input, response, false)
+	scope.done(outcome, nil)
+	return response, nil
+}
+
+// handle calls the request handler, or the fallback handler when no handler matches
+func (skill *Skill) handle(input HandlerInput, scope *requestScope, outcome *string) (*ResponseEnvelope, error) {
+	var handler RequestHandler
+	var consulted []string
+	var err error
+	if skill.Repeat != nil && skill.Repeat.CanHandle(input) {
+		handler = skill.Repeat
+	} else if handler, consulted, err = skill.findHandler(input); err != nil {
+		return nil, err
+	}
+	if handler == nil {
+		if skill.FallbackHandler == nil {
+			input.GetLogger().Debug("No request handler matched", "consulted", consulted)
+			request := input.GetRequest()
+			return nil, &NoHandlerError{RequestType: request.Type, Intent: request.Intent.Name, Consulted: consulted}
+		}
+		handler = &Route{Name: "fallback", Handler: skill.FallbackHandler}
+		*outcome = OutcomeFallback
+	}
+
+	scope.metrics.setHandler(HandlerName(handler))
+	finish := scope.tracer.start(input, SpanHandler, handler)
+	start := time.Now()
+	var response *ResponseEnvelope
+	err = Capture(func() (err error) {
+		response, err = handler.Handle(input)
+		return err
+	})
+	scope.metrics.handled(start)
+	finish(err)
+	if err != nil {
+		return nil, &HandlerError{Handler: HandlerName(handler), Err: err}
+	}
+
+	if response == nil {
+		response = input.GetResponse()
+	}
+	return response, nil
+}
+
+// requestInterceptors adapts the request interceptors into the middleware chain
+func (skill *Skill) requestInterceptors(scope *requestScope) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(input HandlerInput) (*ResponseEnvelope, error) {
+			for _, interceptor := range skill.RequestInterceptors {
+				finish := scope.tracer.start(input, SpanRequestInterceptor, interceptor)
+				start := time.Now()
+				err := Capture(func() error { return interceptor.Process(input) })
+				scope.metrics.interceptor(interceptor, "request", start)
+				finish(err)
+				if err != nil {
+					return nil, &InterceptorError{Interceptor: HandlerName(interceptor), Phase: "request", Err: err}
+				}
+			}
+			return next(input)
+		}
+	}
+}
+
+// responseInterceptors adapts the response interceptors into the middleware chain,
+// they run when the rest of the chain succeeds and are never given a nil response.  They are
+// skipped once the request was answered by the timeout, and once they start the timeout waits
+// for them.
+func (skill *Skill) responseInterceptors(scope *requestScope) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(input HandlerInput) (*ResponseEnvelope, error) {
+			response, err := next(input)
 			if err != nil {
-				return skill.dispatchError(input, err)
+				return nil, err
 			}
-			break
+			if response == nil {
+				response = input.GetResponse()
+			}
+			if !scope.finish(input.GetContext()) {
+				return response, nil
+			}
+
+			for _, interceptor := range skill.ResponseInterceptors {
+				finish := scope.tracer.start(input, SpanResponseInterceptor, interceptor)
+				start := time.Now()
+				err := Capture(func() error { return interceptor.Process(input, response) })
+				scope.metrics.interceptor(interceptor, "response", start)
+				finish(err)
+				if err != nil {
+					return nil, &InterceptorError{Interceptor: HandlerName(interceptor), Phase: "response", Err: err}
+				}
+			}
+			return response, nil
 		}
 	}
+}
 
-	for _, interceptor := range skill.ResponseInterceptors {
-		if err := interceptor.Process(input, response); err != nil {
-			return skill.dispatchError(input, err)
+// findHandler returns the first handler that can handle the request, and the names
+// of the handlers consulted
+func (skill *Skill) findHandler(input HandlerInput) (RequestHandler, []string, error) {
+	consulted := make([]string, 0, len(skill.Handlers))
+	for _, handler := range skill.Handlers {
+		name := HandlerName(handler)
+		consulted = append(consulted, name)
+
+		var matched bool
+		if err := Capture(func() error {
+			matched = handler.CanHandle(input)
+			return nil
+		}); err != nil {
+			return nil, consulted, &HandlerError{Handler: name, Err: err}
+		}
+		if matched {
+			return handler, consulted, nil
 		}
 	}
+	return nil, consulted, nil
+}
 
-	return response, nil
+// start sets up the instrumentation and the logger of a request
+func (skill *Skill) start(input HandlerInput) (*requestScope, Logger) {
+	envelope := input.GetRequestEnvelope()
+
+	if input.GetContext() == nil {
+		input.SetContext(context.Background())
+	}
+
+	scope := &requestScope{
+		metrics: newRequestMetrics(skill.Metrics, envelope.Request),
+		tracer:  startRequestSpan(skill.TracerProvider, input, envelope),
+	}
+
+	logger := skill.Logger
+	if logger == nil {
+		logger = NewSlogLogger(nil)
+	}
+	logger = requestLogger(logger, envelope)
+	input.SetContext(WithLogger(input.GetContext(), logger))
+
+	return scope, logger
+}
+
+// reject passes a request that failed verification or could not be decoded to the error
+// handlers, without calling the interceptors or request handlers
+func (skill *Skill) reject(input HandlerInput, scope *requestScope, err error) (interface{}, error) {
+	input.GetLogger().Warn("Request rejected", "error", err)
+	return skill.dispatchError(input, scope, err)
+}
+
+// requestScope holds the instrumentation of a single request
+type requestScope struct {
+	metrics *requestMetrics
+	tracer  *requestTracer
+	once    sync.Once
+
+	mutex sync.Mutex
+	err   error
+	state int
+	// deadline is set when the request is processed with a deadline
+	deadline bool
+}
+
+// States of a request processed with a deadline, either the handler or the timeout answers it
+const (
+	requestRunning = iota
+	requestFinished
+	requestTimedOut
+)
+
+// setCause keeps the first error dispatched to the error handlers
+func (scope *requestScope) setCause(err error) {
+	scope.mutex.Lock()
+	defer scope.mutex.Unlock()
+
+	if scope.err == nil {
+		scope.err = err
+	}
+}
+
+// finish claims the answer of the request for the handler, it returns false when the request
+// was, or is about to be, answered by the timeout
+func (scope *requestScope) finish(ctx context.Context) bool {
+	scope.mutex.Lock()
+	defer scope.mutex.Unlock()
+
+	if scope.deadline && ctx.Err() != nil && scope.state == requestRunning {
+		return false
+	}
+	if scope.state == requestRunning {
+		scope.state = requestFinished
+	}
+	return scope.state == requestFinished
+}
+
+// timeOut claims the answer of the request for the timeout, it returns false when the handler
+// already finished
+func (scope *requestScope) timeOut() bool {
+	scope.mutex.Lock()
+	defer scope.mutex.Unlock()
+
+	if scope.state == requestRunning {
+		scope.state = requestTimedOut
+	}
+	return scope.state == requestTimedOut
+}
+
+// cause returns the error dispatched to the error handlers, or nil
+func (scope *requestScope) cause() error {
+	scope.mutex.Lock()
+	defer scope.mutex.Unlock()
+
+	return scope.err
+}
+
+// done records the outcome of the request, only the first call counts as a request
+// that timed out finishes after the timeout was handled
+func (scope *requestScope) done(outcome string, err error) {
+	scope.once.Do(func() {
+		scope.metrics.done(outcome)
+		scope.tracer.end(outcome, err)
+	})
 }
 
-func (skill *Skill) dispatchError(input HandlerInput, err error) (interface{}, error) {
+func (skill *Skill) dispatchError(input HandlerInput, scope *requestScope, err error) (interface{}, error) {
+	scope.setCause(err)
+	handled, failed := OutcomeHandledError, OutcomeError
+	var timeoutErr *TimeoutError
+	var verificationErr *VerificationError
+	var decodeErr *DecodeError
+	if errors.Is(err, ErrNoHandler) {
+		handled, failed = OutcomeNoHandler, OutcomeNoHandler
+	} else if errors.As(err, &timeoutErr) {
+		handled, failed = OutcomeTimeout, OutcomeTimeout
+	} else if errors.As(err, &verificationErr) || errors.As(err, &decodeErr) {
+		handled, failed = OutcomeRejected, OutcomeRejected
+	}
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		input.GetLogger().Error("Recovered from panic", "error", err, "stack", string(panicErr.Stack))
+	}
+
 	for _, handler := range skill.ErrorHandlers {
-		if handler.CanHandle(input, err) {
-			return handler.Handle(input, err)
+		var matched bool
+		if handlerErr := Capture(func() error {
+			matched = handler.CanHandle(input, err)
+			return nil
+		}); handlerErr != nil {
+			handlerErr = &ErrorHandlerError{Handler: HandlerName(handler), Err: handlerErr, Cause: err}
+			scope.done(failed, handlerErr)
+			return nil, handlerErr
+		}
+		if !matched {
+			continue
+		}
+
+		scope.metrics.dispatched(handler)
+		finish := scope.tracer.start(input, SpanErrorHandler, handler)
+		var response *ResponseEnvelope
+		handlerErr := Capture(func() (handlerErr error) {
+			response, handlerErr = handler.Handle(input, err)
+			return handlerErr
+		})
+		finish(handlerErr)
+
+		// Keep the original error along with the one of the error handler
+		if handlerErr != nil && handlerErr != err {
+			handlerErr = &ErrorHandlerError{Handler: HandlerName(handler), Err: handlerErr, Cause: err}
+		}
+		if handlerErr != nil {
+			scope.done(failed, handlerErr)
+		} else {
+			skill.Repeat.remember(input, response, true)
+			scope.done(handled, nil)
 		}
+		return response, handlerErr
 	}
 
+	scope.metrics.dispatched(nil)
+	scope.done(failed, err)
 	return nil, err
 }
 
@@ -245,3 +627,13 @@ func (handler *DefaultHandler) GetContext() context.Context {
 func (handler *DefaultHandler) SetContext(ctx context.Context) {
 	handler.context = ctx
 }
+
+// T returns the localized message from the message catalogs of the skill
+func (handler *DefaultHandler) T(key string, args ...interface{}) string {
+	return i18n.FromContext(handler.context).T(key, args...)
+}
+
+// GetLogger returns the request logger set up by the skill
+func (handler *DefaultHandler) GetLogger() Logger {
+	return LoggerFromContext(handler.context)
+}
@@ -0,0 +1,80 @@
+package askgo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/stretchr/testify/require"
+)
+
+type attributeSaver struct {
+	seen []*askgo.ResponseEnvelope
+}
+
+func (saver *attributeSaver) Process(input askgo.HandlerInput, response *askgo.ResponseEnvelope) error {
+	saver.seen = append(saver.seen, response)
+	response.SessionAttributes = map[string]interface{}{"saved": true}
+	return nil
+}
+
+type errorCatcher struct {
+	caught error
+}
+
+func (catcher *errorCatcher) CanHandle(input askgo.HandlerInput, err error) bool {
+	return errors.Is(err, askgo.ErrNoHandler)
+}
+
+func (catcher *errorCatcher) Handle(input askgo.HandlerInput, err error) (*askgo.ResponseEnvelope, error) {
+	catcher.caught = err
+	return input.GetResponse().Speak("I can't help with that"), nil
+}
+
+func intentRequest(name string) *askgo.RequestEnvelope {
+	return &askgo.RequestEnvelope{Request: alexa.Request{Type: "IntentRequest", Intent: alexa.Intent{Name: name}}}
+}
+
+func Test_NoHandler(t *testing.T) {
+	saver := &attributeSaver{}
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		Handlers: []askgo.RequestHandler{
+			&askgo.Route{Name: "launch", RequestType: "LaunchRequest", Handler: func(askgo.HandlerInput) (*askgo.ResponseEnvelope, error) { return nil, nil }},
+			&askgo.Route{Name: "help", Intents: []string{alexa.HelpIntent}, Handler: func(askgo.HandlerInput) (*askgo.ResponseEnvelope, error) { return nil, nil }},
+		},
+		ResponseInterceptors: []askgo.ResponseInterceptor{saver},
+	}
+
+	// A handler returning nil still gives the interceptors an envelope
+	result, err := skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), intentRequest(alexa.HelpIntent)))
+	require.NoError(t, err)
+	require.Len(t, saver.seen, 1)
+	require.NotNil(t, saver.seen[0])
+	require.Equal(t, true, result.(*askgo.ResponseEnvelope).SessionAttributes["saved"])
+
+	_, err = skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), intentRequest("OrderIntent")))
+	require.True(t, errors.Is(err, askgo.ErrNoHandler))
+	var noHandler *askgo.NoHandlerError
+	require.True(t, errors.As(err, &noHandler))
+	require.Equal(t, []string{"launch", "help"}, noHandler.Consulted)
+	require.Equal(t, "askgo: no request handler can handle IntentRequest OrderIntent (consulted: launch, help)", err.Error())
+	require.Len(t, saver.seen, 1)
+
+	catcher := &errorCatcher{}
+	skill.ErrorHandlers = []askgo.ErrorHandler{catcher}
+	result, err = skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), intentRequest("OrderIntent")))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.True(t, errors.Is(catcher.caught, askgo.ErrNoHandler))
+
+	skill.FallbackHandler = func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+		return input.GetResponse().Speak("Fallback"), nil
+	}
+	result, err = skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), intentRequest("OrderIntent")))
+	require.NoError(t, err)
+	require.Equal(t, "<speak>Fallback</speak>", result.(*askgo.ResponseEnvelope).Response.OutputSpeech.SSML)
+	require.Len(t, saver.seen, 2)
+}
@@ -0,0 +1,131 @@
+// Package record captures the requests and responses of a skill so that problems seen in
+// production can be replayed against the current code.
+//
+// A Recorder observes the requests of the skill:
+//
+//	recorder := &record.Recorder{Sink: record.NewWriterSink(os.Stderr), Redaction: record.RedactAll}
+//	skill.Observers = append(skill.Observers, recorder)
+//
+// The Recorder is an askgo.RequestObserver rather than a pair of request and response
+// interceptors: an observer is also told about the requests that fail or time out before the
+// response interceptors run, and gets the error along with the response.
+//
+// The recordings can be replayed with Replay, or with askgo-sim -replay.
+package record
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/koblas/askgo"
+)
+
+// Record is a single request and the response produced by the skill
+type Record struct {
+	Time     time.Time               `json:"time"`
+	Request  *askgo.RequestEnvelope  `json:"request"`
+	Response *askgo.ResponseEnvelope `json:"response"`
+	// Error is the error of the request, also when an error handler answered it
+	Error string `json:"error,omitempty"`
+}
+
+// Sink stores records
+type Sink interface {
+	Write(ctx context.Context, record *Record) error
+}
+
+// RedactedValue replaces redacted values
+const RedactedValue = "<redacted>"
+
+// Redaction selects the values removed from recorded requests
+type Redaction struct {
+	// AccessToken is the account linking token of the user and of the recognized person
+	AccessToken bool
+	// APIAccessToken is the token used to call the Alexa APIs
+	APIAccessToken bool
+	// UserID is the user ID of the session and context, and the ID of the recognized person
+	UserID bool
+}
+
+// RedactAll removes all the sensitive values supported
+var RedactAll = Redaction{AccessToken: true, APIAccessToken: true, UserID: true}
+
+// Apply removes the selected values from the envelope
+func (redaction Redaction) Apply(envelope *askgo.RequestEnvelope) {
+	redact := func(value *string, enabled bool) {
+		if enabled && *value != "" {
+			*value = RedactedValue
+		}
+	}
+
+	redact(&envelope.Session.User.AccessToken, redaction.AccessToken)
+	redact(&envelope.Context.System.User.AccessToken, redaction.AccessToken)
+	redact(&envelope.Context.System.APIAccessToken, redaction.APIAccessToken)
+	redact(&envelope.Session.User.UserID, redaction.UserID)
+	redact(&envelope.Context.System.User.UserID, redaction.UserID)
+	if person := envelope.Context.System.Person; person != nil {
+		redact(&person.AccessToken, redaction.AccessToken)
+		redact(&person.PersonID, redaction.UserID)
+	}
+}
+
+// Recorder writes a Record for every request, including those that fail, time out or are
+// answered by an error handler.  It is a RequestObserver of the skill.
+type Recorder struct {
+	Sink      Sink
+	Redaction Redaction
+	// OnError is called when the sink fails, the default logs the error with the request logger.
+	// Recording failures never fail the request.
+	OnError func(err error)
+}
+
+var _ askgo.RequestObserver = &Recorder{}
+
+// Observe captures the request before the handlers run, and writes it with the response or
+// error to the sink once the request is over
+func (recorder *Recorder) Observe(input askgo.HandlerInput) func(response *askgo.ResponseEnvelope, err error) {
+	envelope := input.GetRequestEnvelope()
+
+	// Copy through JSON, handlers are free to change the session attributes
+	request := &askgo.RequestEnvelope{}
+	if err := copyJSON(&envelope, request); err != nil {
+		recorder.fail(input, err)
+		return func(*askgo.ResponseEnvelope, error) {}
+	}
+	recorder.Redaction.Apply(request)
+	record := &Record{Time: time.Now().UTC(), Request: request}
+
+	return func(response *askgo.ResponseEnvelope, err error) {
+		if err != nil {
+			record.Error = err.Error()
+		}
+		if response != nil {
+			record.Response = &askgo.ResponseEnvelope{}
+			if err := copyJSON(response, record.Response); err != nil {
+				recorder.fail(input, err)
+				return
+			}
+		}
+
+		if err := recorder.Sink.Write(input.GetContext(), record); err != nil {
+			recorder.fail(input, err)
+		}
+	}
+}
+
+func (recorder *Recorder) fail(input askgo.HandlerInput, err error) {
+	if recorder.OnError != nil {
+		recorder.OnError(err)
+	} else {
+		input.GetLogger().Error("Unable to record request", "error", err)
+	}
+}
+
+func copyJSON(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
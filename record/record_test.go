@@ -0,0 +1,117 @@
+package record_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/askgotest"
+	"github.com/koblas/askgo/record"
+	"github.com/stretchr/testify/require"
+)
+
+func greetingSkill(greeting string) *askgo.Skill {
+	return &askgo.Skill{
+		Handlers: []askgo.RequestHandler{
+			askgo.RequestRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				input.GetRequestEnvelope().Session.Attributes["visits"] = 2.0
+				response := input.GetResponse().Speak(greeting)
+				response.SessionAttributes = map[string]interface{}{"visits": 2}
+				return response, nil
+			}, "LaunchRequest"),
+		},
+	}
+}
+
+func Test_RecordReplay(t *testing.T) {
+	sink := &record.MemorySink{}
+	recorder := &record.Recorder{Sink: sink, Redaction: record.Redaction{APIAccessToken: true, UserID: true}}
+
+	skill := greetingSkill("Hello")
+	skill.Observers = []askgo.RequestObserver{recorder}
+
+	conv := askgotest.NewConversation(skill)
+	envelope := conv.Prepare(askgotest.NewLaunchRequest())
+	envelope.Session.Attributes = map[string]interface{}{"visits": 1.0}
+	envelope.Session.User.AccessToken = "linked-token"
+	_, err := skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), envelope))
+	require.NoError(t, err)
+
+	records := sink.Records()
+	require.Len(t, records, 1)
+	recorded := records[0]
+	require.Equal(t, record.RedactedValue, recorded.Request.Context.System.APIAccessToken)
+	require.Equal(t, record.RedactedValue, recorded.Request.Session.User.UserID)
+	require.Equal(t, "linked-token", recorded.Request.Session.User.AccessToken)
+	require.Equal(t, 1.0, recorded.Request.Session.Attributes["visits"])
+	require.Equal(t, "Hello", askgotest.Speech(recorded.Response))
+
+	// Round trip through the line format
+	buf := &bytes.Buffer{}
+	require.NoError(t, record.NewWriterSink(buf).Write(context.Background(), recorded))
+	loaded, err := record.Read(bytes.NewReader(append([]byte("START RequestId: 1\nINFO "), buf.Bytes()...)))
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+
+	replaying := greetingSkill("Hello")
+	replaying.Observers = []askgo.RequestObserver{recorder}
+	result := record.Replay(replaying, loaded[0])
+	require.NoError(t, result.Err)
+	require.False(t, result.Changed())
+
+	result = record.Replay(greetingSkill("Goodbye"), loaded[0])
+	require.True(t, result.Changed())
+	require.Contains(t, result.Diff, `-      "ssml": "<speak>Hello</speak>",`)
+	require.Contains(t, result.Diff, `+      "ssml": "<speak>Goodbye</speak>",`)
+	require.Len(t, sink.Records(), 1)
+}
+
+func Test_RecordFailures(t *testing.T) {
+	sink := &record.MemorySink{}
+	skill := &askgo.Skill{
+		Handlers: []askgo.RequestHandler{
+			askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				return nil, errors.New("database unavailable")
+			}, "BrokenIntent"),
+		},
+		Observers: []askgo.RequestObserver{&record.Recorder{Sink: sink}},
+	}
+	conv := askgotest.NewConversation(skill)
+
+	_, err := conv.Intent("BrokenIntent", nil)
+	require.Error(t, err)
+
+	skill.ErrorHandlers = []askgo.ErrorHandler{askgo.TimeoutHandler("unused"), apology{}}
+	response, err := conv.Intent("BrokenIntent", nil)
+	require.NoError(t, err)
+	require.Equal(t, "Sorry", askgotest.Speech(response))
+
+	records := sink.Records()
+	require.Len(t, records, 2)
+	require.Nil(t, records[0].Response)
+	require.Contains(t, records[0].Error, "database unavailable")
+	require.Equal(t, "Sorry", askgotest.Speech(records[1].Response))
+	require.Contains(t, records[1].Error, "database unavailable")
+
+	// A failure replayed with the same error is unchanged
+	require.False(t, record.Replay(skill, records[1]).Changed())
+	skill.ErrorHandlers = nil
+	result := record.Replay(skill, records[0])
+	require.Error(t, result.Err)
+	require.False(t, result.Changed())
+
+	skill.Handlers[0].(*askgo.Route).Handler = func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+		return nil, errors.New("cache unavailable")
+	}
+	require.True(t, record.Replay(skill, records[0]).Changed())
+}
+
+type apology struct{}
+
+func (apology) CanHandle(input askgo.HandlerInput, err error) bool { return true }
+
+func (apology) Handle(input askgo.HandlerInput, err error) (*askgo.ResponseEnvelope, error) {
+	return input.GetResponse().Speak("Sorry"), nil
+}
@@ -0,0 +1,117 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/koblas/askgo"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Result is the outcome of replaying a record
+type Result struct {
+	Record   *Record
+	Response *askgo.ResponseEnvelope
+	// Err is the error of the replayed request, or the failure to replay it
+	Err error
+	// Diff is the unified diff from the recorded to the new response, empty when they match
+	Diff string
+}
+
+// Changed reports whether the skill no longer produces the recorded response, or fails
+// with an error other than the recorded one
+func (result Result) Changed() bool {
+	if result.Err != nil {
+		return result.Record == nil || result.Err.Error() != result.Record.Error
+	}
+	return result.Diff != ""
+}
+
+// Replay processes the recorded request with the skill and compares the response with
+// the recorded one.  Timestamp verification is bypassed and the observers of the skill, such
+// as a Recorder, are not called.
+//
+// The rest of the skill is used as is: the response interceptors run and the Persistence
+// adapter is read and written.  Replay production records against a skill configured with a
+// separate store, e.g. an askgo.MemoryPersistence:
+//
+//	replaying := *skill
+//	replaying.Persistence = &askgo.Persistence{Adapter: &askgo.MemoryPersistence{}}
+//	results := record.ReplayAll(&replaying, records)
+func Replay(skill *askgo.Skill, record *Record) Result {
+	replayed := *skill
+	replayed.IgnoreTimestamp = true
+	replayed.Observers = nil
+
+	// Work on a copy so the record can be replayed again
+	envelope := &askgo.RequestEnvelope{}
+	if err := copyJSON(record.Request, envelope); err != nil {
+		return Result{Record: record, Err: err}
+	}
+
+	output, err := replayed.ProcessRequest(askgo.NewDefaultHandler(context.Background(), envelope))
+	response, _ := output.(*askgo.ResponseEnvelope)
+	result := Result{Record: record, Response: response, Err: err}
+	if err != nil {
+		return result
+	}
+
+	expected, err := canonical(record.Response)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	actual, err := canonical(response)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	if expected != actual {
+		result.Diff, _ = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(expected),
+			B:        difflib.SplitLines(actual),
+			FromFile: "recorded",
+			ToFile:   "replayed",
+			Context:  3,
+		})
+	}
+
+	return result
+}
+
+// ReplayAll replays each record in order
+func ReplayAll(skill *askgo.Skill, records []*Record) []Result {
+	results := make([]Result, 0, len(records))
+	for _, record := range records {
+		results = append(results, Replay(skill, record))
+	}
+	return results
+}
+
+// canonical renders the response as indented JSON with sorted keys
+func canonical(response *askgo.ResponseEnvelope) (string, error) {
+	if response == nil {
+		return "null\n", nil
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return "", err
+	}
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return "", err
+	}
+
+	// Keep the SSML readable
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(tree); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
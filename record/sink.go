@@ -0,0 +1,148 @@
+package record
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WriterSink writes each record as a line of JSON (e.g. to stderr for CloudWatch)
+type WriterSink struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// NewWriterSink creates a sink writing to w
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{writer: w}
+}
+
+// Write implements Sink
+func (sink *WriterSink) Write(ctx context.Context, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	_, err = sink.writer.Write(append(data, '\n'))
+	return err
+}
+
+// DirSink writes each record to its own file in a directory, named after the request ID
+type DirSink struct {
+	Dir string
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Write implements Sink
+func (sink *DirSink) Write(ctx context.Context, record *Record) error {
+	name := unsafeFileChars.ReplaceAllString(record.Request.Request.RequestID, "_")
+	if name == "" {
+		name = fmt.Sprintf("%d", record.Time.UnixNano())
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(sink.Dir, name+".json"), data, 0644)
+}
+
+// MemorySink keeps the records in memory, mainly for tests
+type MemorySink struct {
+	mutex   sync.Mutex
+	records []*Record
+}
+
+// Write implements Sink
+func (sink *MemorySink) Write(ctx context.Context, record *Record) error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	sink.records = append(sink.records, record)
+	return nil
+}
+
+// Records returns the records written so far
+func (sink *MemorySink) Records() []*Record {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	return append([]*Record(nil), sink.records...)
+}
+
+// Read decodes a stream of JSON records as written by WriterSink, lines that
+// are not records (e.g. other log output) are skipped
+func Read(r io.Reader) ([]*Record, error) {
+	records := make([]*Record, 0)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if idx := strings.Index(line, "{"); idx > 0 {
+			line = line[idx:]
+		}
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		record := &Record{}
+		if err := json.Unmarshal([]byte(line), record); err != nil || record.Request == nil {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, scanner.Err()
+}
+
+// Load reads the records of a file, or of every *.json file of a directory.
+// A file holds either a single record or one record per line.
+func Load(path string) ([]*Record, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "*.json")); err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	records := make([]*Record, 0)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		record := &Record{}
+		if err := json.Unmarshal(data, record); err == nil && record.Request != nil {
+			records = append(records, record)
+			continue
+		}
+
+		found, err := Read(strings.NewReader(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		records = append(records, found...)
+	}
+
+	return records, nil
+}
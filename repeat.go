@@ -0,0 +1,126 @@
+package askgo
+
+import (
+	"encoding/json"
+
+	"github.com/koblas/askgo/alexa"
+)
+
+// RepeatAttribute is the session attribute holding the last response remembered by Repeat
+const RepeatAttribute = "REPEAT"
+
+// Repeat remembers the speech, reprompt and card of the last response in the session
+// attributes, and handles AMAZON.RepeatIntent by sending them again.  When set as
+// Skill.Repeat it is consulted before the handlers of the skill.
+//
+//	skill.Repeat = &askgo.Repeat{SkipIntents: []string{alexa.HelpIntent}, SkipErrors: true}
+type Repeat struct {
+	// Attribute is the session attribute holding the last response, defaults to RepeatAttribute
+	Attribute string
+	// SkipIntents are the intents whose responses are not remembered (e.g. alexa.HelpIntent)
+	SkipIntents []string
+	// SkipErrors does not remember the responses of the error handlers
+	SkipErrors bool
+}
+
+var _ RequestHandler = &Repeat{}
+var _ HandlerNamer = &Repeat{}
+
+// lastResponse is the part of a response that is repeated
+type lastResponse struct {
+	OutputSpeech *alexa.OutputSpeech `json:"outputSpeech,omitempty"`
+	Reprompt     *alexa.Reprompt     `json:"reprompt,omitempty"`
+	Card         *alexa.Card         `json:"card,omitempty"`
+}
+
+func (repeat *Repeat) attribute() string {
+	if repeat.Attribute != "" {
+		return repeat.Attribute
+	}
+	return RepeatAttribute
+}
+
+// last returns the response remembered in the session, or nil
+func (repeat *Repeat) last(input HandlerInput) *lastResponse {
+	value, found := input.GetRequestEnvelope().Session.Attributes[repeat.attribute()]
+	if !found {
+		return nil
+	}
+
+	// Session attributes come back from Alexa as generic JSON values
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	last := &lastResponse{}
+	if err := json.Unmarshal(data, last); err != nil || last.OutputSpeech == nil {
+		return nil
+	}
+	return last
+}
+
+// CanHandle accepts AMAZON.RepeatIntent when a response was remembered
+func (repeat *Repeat) CanHandle(input HandlerInput) bool {
+	request := input.GetRequest()
+	return request.Type == "IntentRequest" && request.Intent.Name == alexa.RepeatIntent && repeat.last(input) != nil
+}
+
+// Handle sends the remembered response again, keeping the session attributes
+func (repeat *Repeat) Handle(input HandlerInput) (*ResponseEnvelope, error) {
+	last := repeat.last(input)
+	response := input.GetResponse()
+	if last == nil {
+		return response, nil
+	}
+
+	for key, value := range input.GetRequestEnvelope().Session.Attributes {
+		if _, found := response.SessionAttributes[key]; !found {
+			response.WithSessionAttribute(key, value)
+		}
+	}
+	body := response.getResponse()
+	body.OutputSpeech = last.OutputSpeech
+	body.Reprompt = last.Reprompt
+	body.Card = last.Card
+
+	return response, nil
+}
+
+// HandlerName returns "repeat"
+func (repeat *Repeat) HandlerName() string {
+	return "repeat"
+}
+
+// remember stores the speech of the response in its session attributes, a skipped response
+// keeps the one remembered before so it can still be repeated
+func (repeat *Repeat) remember(input HandlerInput, response *ResponseEnvelope, fromError bool) {
+	if repeat == nil || response == nil || response.Response == nil || response.Response.ShouldSessionEnd {
+		return
+	}
+
+	if response.Response.OutputSpeech == nil || (fromError && repeat.SkipErrors) || repeat.skipped(input.GetRequest()) {
+		previous, found := input.GetRequestEnvelope().Session.Attributes[repeat.attribute()]
+		if _, set := response.SessionAttributes[repeat.attribute()]; found && !set {
+			response.WithSessionAttribute(repeat.attribute(), previous)
+		}
+		return
+	}
+
+	response.WithSessionAttribute(repeat.attribute(), &lastResponse{
+		OutputSpeech: response.Response.OutputSpeech,
+		Reprompt:     response.Response.Reprompt,
+		Card:         response.Response.Card,
+	})
+}
+
+func (repeat *Repeat) skipped(request Request) bool {
+	if request.Type != "IntentRequest" {
+		return false
+	}
+	for _, name := range repeat.SkipIntents {
+		if name == request.Intent.Name {
+			return true
+		}
+	}
+	return false
+}
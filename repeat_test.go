@@ -0,0 +1,74 @@
+package askgo_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/koblas/askgo/askgotest"
+	"github.com/stretchr/testify/require"
+)
+
+type sorryHandler struct{}
+
+func (sorryHandler) CanHandle(input askgo.HandlerInput, err error) bool { return true }
+
+func (sorryHandler) Handle(input askgo.HandlerInput, err error) (*askgo.ResponseEnvelope, error) {
+	return input.GetResponse().Speak("Sorry"), nil
+}
+
+func Test_Repeat(t *testing.T) {
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		Handlers: []askgo.RequestHandler{
+			askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				return input.GetResponse().Speak("What is the capital of France?").Reprompt("Which city?").
+					WithSimpleCard("Quiz", "Capital of France").WithSessionAttribute("score", 1), nil
+			}, "QuizIntent"),
+			askgo.IntentRoute(say("Answer the question"), alexa.HelpIntent),
+			askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				return nil, errors.New("broken")
+			}, "BrokenIntent"),
+		},
+		ErrorHandlers: []askgo.ErrorHandler{sorryHandler{}},
+		Repeat:        &askgo.Repeat{SkipIntents: []string{alexa.HelpIntent}, SkipErrors: true},
+	}
+	conv := askgotest.NewConversation(skill)
+
+	conv.Run(t, askgotest.Script{
+		{Intent: "QuizIntent", Expect: askgotest.Expect{Speech: "What is the capital of France?"}},
+		{Intent: alexa.RepeatIntent, Expect: askgotest.Expect{Speech: "What is the capital of France?"}},
+	})
+	// Repeating keeps the other session attributes
+	require.EqualValues(t, 1, conv.SessionAttributes()["score"])
+
+	conv.Run(t, askgotest.Script{
+		{Intent: alexa.HelpIntent, Expect: askgotest.Expect{Speech: "Answer the question"}},
+		{Intent: "BrokenIntent", Expect: askgotest.Expect{Speech: "Sorry"}},
+		{Intent: alexa.RepeatIntent, Expect: askgotest.Expect{Speech: "What is the capital of France?", Reprompt: "Which city?", CardTitle: "Quiz"}},
+	})
+
+	// Nothing to repeat in a new session, the request goes to the error handlers
+	conv.Reset()
+	response, err := conv.Intent(alexa.RepeatIntent, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Sorry", askgotest.Speech(response))
+}
+
+func Test_RepeatBeforeStateMachine(t *testing.T) {
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		Handlers:        []askgo.RequestHandler{quizMachine()},
+		Repeat:          &askgo.Repeat{},
+	}
+	conv := askgotest.NewConversation(skill)
+
+	// The Unhandled handler of the state would take AMAZON.RepeatIntent
+	conv.Run(t, askgotest.Script{
+		{Intent: "QuizIntent", Expect: askgotest.Expect{Speech: "First question"}},
+		{Intent: alexa.RepeatIntent, Expect: askgotest.Expect{Speech: "First question"}},
+		{Intent: "PizzaIntent", Expect: askgotest.Expect{Speech: "Please answer the question"}},
+	})
+	require.Equal(t, "QUIZ", conv.SessionAttributes()[askgo.StateAttribute])
+}
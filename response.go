@@ -30,7 +30,14 @@ type ResponseBuilder interface {
 	AddRenderTemplateDirective(template alexa.DisplayTemplate) *ResponseEnvelope
 	AddHintDirective(text string) *ResponseEnvelope
 	AddVideoAppLaunchDirective(source string, title, subtitle *string) *ResponseEnvelope
+	AddGameEngineStartInputHandlerDirective(timeout int, recognizers map[string]alexa.Recognizer, events map[string]alexa.GameEngineEvent) *ResponseEnvelope
+	AddGameEngineStopInputHandlerDirective(originatingRequestID string) *ResponseEnvelope
+	AddGadgetControllerSetLightDirective(targetGadgets []string, triggerEvent string, triggerEventTimeMs int, animations ...alexa.LightAnimation) *ResponseEnvelope
+	AddCustomInterfaceStartEventHandlerDirective(token string, filterExpression interface{}, filterMatchAction string, durationInMilliseconds int, expirationPayload map[string]interface{}) *ResponseEnvelope
+	AddCustomInterfaceStopEventHandlerDirective(token string) *ResponseEnvelope
+	AddCustomInterfaceSendDirective(endpointID, namespace, name string, payload interface{}) *ResponseEnvelope
 	WithShouldEndSession(val bool) *ResponseEnvelope
+	WithSessionAttribute(key string, value interface{}) *ResponseEnvelope
 	AddDirective(directive interface{}) *ResponseEnvelope
 	GetResponse() *ResponseEnvelope
 }
@@ -262,6 +269,74 @@ func (envelope *ResponseEnvelope) AddVideoAppLaunchDirective(source string, titl
 	})
 }
 
+// AddGameEngineStartInputHandlerDirective - listens to Echo Buttons for timeout milliseconds
+func (envelope *ResponseEnvelope) AddGameEngineStartInputHandlerDirective(timeout int, recognizers map[string]alexa.Recognizer, events map[string]alexa.GameEngineEvent) *ResponseEnvelope {
+	return envelope.AddDirective(&alexa.GameEngineStartInputHandlerDirective{
+		Type:        "GameEngine.StartInputHandler",
+		Timeout:     timeout,
+		Recognizers: recognizers,
+		Events:      events,
+	})
+}
+
+// AddGameEngineStopInputHandlerDirective - stops the input handler started by the given request
+func (envelope *ResponseEnvelope) AddGameEngineStopInputHandlerDirective(originatingRequestID string) *ResponseEnvelope {
+	return envelope.AddDirective(&alexa.GameEngineStopInputHandlerDirective{
+		Type:                 "GameEngine.StopInputHandler",
+		OriginatingRequestID: originatingRequestID,
+	})
+}
+
+// AddGadgetControllerSetLightDirective - animates the lights of the target gadgets (all when empty)
+func (envelope *ResponseEnvelope) AddGadgetControllerSetLightDirective(targetGadgets []string, triggerEvent string, triggerEventTimeMs int, animations ...alexa.LightAnimation) *ResponseEnvelope {
+	return envelope.AddDirective(&alexa.GadgetControllerSetLightDirective{
+		Type:          "GadgetController.SetLight",
+		Version:       1,
+		TargetGadgets: targetGadgets,
+		Parameters: alexa.SetLightParameters{
+			TriggerEvent:       triggerEvent,
+			TriggerEventTimeMs: triggerEventTimeMs,
+			Animations:         animations,
+		},
+	})
+}
+
+// AddCustomInterfaceStartEventHandlerDirective - receives the custom events of gadgets matching the
+// JSON Logic filter (nil for every event) for the duration
+func (envelope *ResponseEnvelope) AddCustomInterfaceStartEventHandlerDirective(token string, filterExpression interface{}, filterMatchAction string, durationInMilliseconds int, expirationPayload map[string]interface{}) *ResponseEnvelope {
+	directive := &alexa.CustomInterfaceStartEventHandlerDirective{
+		Type:  "CustomInterfaceController.StartEventHandler",
+		Token: token,
+		Expiration: alexa.EventExpiration{
+			DurationInMilliseconds: durationInMilliseconds,
+			ExpirationPayload:      expirationPayload,
+		},
+	}
+	if filterExpression != nil {
+		directive.EventFilter = &alexa.EventFilter{FilterExpression: filterExpression, FilterMatchAction: filterMatchAction}
+	}
+
+	return envelope.AddDirective(directive)
+}
+
+// AddCustomInterfaceStopEventHandlerDirective - stops the event handler with the token
+func (envelope *ResponseEnvelope) AddCustomInterfaceStopEventHandlerDirective(token string) *ResponseEnvelope {
+	return envelope.AddDirective(&alexa.CustomInterfaceStopEventHandlerDirective{
+		Type:  "CustomInterfaceController.StopEventHandler",
+		Token: token,
+	})
+}
+
+// AddCustomInterfaceSendDirective - sends a custom directive to the gadget with the endpoint ID
+func (envelope *ResponseEnvelope) AddCustomInterfaceSendDirective(endpointID, namespace, name string, payload interface{}) *ResponseEnvelope {
+	return envelope.AddDirective(&alexa.CustomInterfaceSendDirective{
+		Type:     "CustomInterfaceController.SendDirective",
+		Header:   alexa.Header{Namespace: namespace, Name: name},
+		Endpoint: alexa.Endpoint{EndpointID: endpointID},
+		Payload:  payload,
+	})
+}
+
 // AddDirective - helper method for adding directives to responses
 func (envelope *ResponseEnvelope) AddDirective(directive interface{}) *ResponseEnvelope {
 	response := envelope.getResponse()
@@ -289,6 +364,16 @@ func (envelope *ResponseEnvelope) WithShouldEndSession(val bool) *ResponseEnvelo
 	return envelope
 }
 
+// WithSessionAttribute sets an attribute of the session, it is sent back with the next request
+func (envelope *ResponseEnvelope) WithSessionAttribute(key string, value interface{}) *ResponseEnvelope {
+	if envelope.SessionAttributes == nil {
+		envelope.SessionAttributes = make(map[string]interface{})
+	}
+	envelope.SessionAttributes[key] = value
+
+	return envelope
+}
+
 // GetResponse - just return ourself
 func (envelope *ResponseEnvelope) GetResponse() *ResponseEnvelope {
 	return envelope
@@ -1,9 +1,12 @@
 package askgo_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/koblas/askgo/jsonlogic"
 	"github.com/stretchr/testify/require"
 )
 
@@ -14,3 +17,111 @@ func Test_Interface(t *testing.T) {
 
 	require.True(t, env.Response.ShouldSessionEnd, "Session End")
 }
+
+func Test_GadgetDirectives(t *testing.T) {
+	env := &askgo.ResponseEnvelope{}
+	env.AddGameEngineStartInputHandlerDirective(5000,
+		map[string]alexa.Recognizer{
+			"press": alexa.PatternRecognizer{Anchor: "end", Pattern: []alexa.Pattern{{Action: "down"}}},
+			"other": alexa.DeviationRecognizer{Recognizer: "press"},
+		},
+		map[string]alexa.GameEngineEvent{
+			"pressed":  {Meets: []string{"press"}, Reports: "matches", ShouldEndInputHandler: true},
+			"timedOut": {Meets: []string{"timed out"}, Reports: "history", ShouldEndInputHandler: true},
+		})
+	env.AddGadgetControllerSetLightDirective(nil, "none", 0,
+		alexa.NewAnimation().Repeat(2).FadeIn("FF0000", 500).Blink("00FF00", 100, 100, 1).Build())
+
+	data, err := json.Marshal(env.Response.Directives)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"type": "GameEngine.StartInputHandler", "timeout": 5000,
+		 "recognizers": {
+			"press": {"type": "match", "anchor": "end", "fuzzy": false, "pattern": [{"action": "down"}]},
+			"other": {"type": "deviation", "recognizer": "press"}},
+		 "events": {
+			"pressed": {"meets": ["press"], "reports": "matches", "shouldEndInputHandler": true},
+			"timedOut": {"meets": ["timed out"], "reports": "history", "shouldEndInputHandler": true}}},
+		{"type": "GadgetController.SetLight", "version": 1,
+		 "parameters": {"triggerEvent": "none", "triggerEventTimeMs": 0, "animations": [
+			{"repeat": 2, "targetLights": ["1"], "sequence": [
+				{"durationMs": 1, "color": "000000", "blend": false},
+				{"durationMs": 500, "color": "FF0000", "blend": true},
+				{"durationMs": 100, "color": "00FF00", "blend": false},
+				{"durationMs": 100, "color": "000000", "blend": false}]}]}}
+	]`, string(data))
+}
+
+func Test_InputHandlerEvent(t *testing.T) {
+	var envelope askgo.RequestEnvelope
+	require.NoError(t, json.Unmarshal([]byte(`{"request": {
+		"type": "GameEngine.InputHandlerEvent",
+		"originatingRequestId": "amzn1.echo-api.request.1",
+		"events": [{"name": "pressed", "inputEvents": [
+			{"gadgetId": "button1", "timestamp": "2018-01-01T00:00:00.000Z", "color": "FF0000", "feature": "press", "action": "down"}]}]
+	}}`), &envelope))
+
+	request := envelope.Request
+	require.Equal(t, "amzn1.echo-api.request.1", request.OriginatingRequestID)
+	require.Len(t, request.Events, 1)
+	require.Equal(t, "pressed", request.Events[0].Name)
+	require.Equal(t, "button1", request.Events[0].InputEvents[0].GadgetID)
+}
+
+func Test_CustomInterfaceDirectives(t *testing.T) {
+	env := &askgo.ResponseEnvelope{}
+	env.AddCustomInterfaceStartEventHandlerDirective("token-1",
+		jsonlogic.Equal(jsonlogic.Var("header.namespace"), "Custom.Robot"), "SEND", 60000, map[string]interface{}{"done": true})
+	env.AddCustomInterfaceSendDirective("gadget-1", "Custom.Robot", "Spin", map[string]interface{}{"speed": 3})
+	env.AddCustomInterfaceStopEventHandlerDirective("token-1")
+
+	data, err := json.Marshal(env.Response.Directives)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"type": "CustomInterfaceController.StartEventHandler", "token": "token-1",
+		 "eventFilter": {"filterExpression": {"==": [{"var": "header.namespace"}, "Custom.Robot"]}, "filterMatchAction": "SEND"},
+		 "expiration": {"durationInMilliseconds": 60000, "expirationPayload": {"done": true}}},
+		{"type": "CustomInterfaceController.SendDirective", "header": {"namespace": "Custom.Robot", "name": "Spin"},
+		 "endpoint": {"endpointId": "gadget-1"}, "payload": {"speed": 3}},
+		{"type": "CustomInterfaceController.StopEventHandler", "token": "token-1"}
+	]`, string(data))
+}
+
+func Test_EventsReceived(t *testing.T) {
+	var envelope askgo.RequestEnvelope
+	require.NoError(t, json.Unmarshal([]byte(`{"request": {
+		"type": "CustomInterfaceController.EventsReceived",
+		"token": "token-1",
+		"events": [{"header": {"namespace": "Custom.Robot", "name": "Bumped"},
+			"endpoint": {"endpointId": "gadget-1"}, "payload": {"side": "left"}}]
+	}}`), &envelope))
+
+	require.Empty(t, envelope.Request.Events)
+	event := envelope.Request.CustomInterfaceEvents[0]
+	require.Equal(t, "token-1", envelope.Request.Token)
+	require.Equal(t, "Bumped", event.Header.Name)
+	require.Equal(t, "gadget-1", event.Endpoint.EndpointID)
+	require.Equal(t, "left", event.Payload["side"])
+
+	// The events survive a round trip, e.g. through a recording
+	data, err := json.Marshal(envelope)
+	require.NoError(t, err)
+	var decoded askgo.RequestEnvelope
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, envelope.Request.CustomInterfaceEvents, decoded.Request.CustomInterfaceEvents)
+}
+
+func Test_SlotResolutions(t *testing.T) {
+	var envelope askgo.RequestEnvelope
+	require.NoError(t, json.Unmarshal([]byte(`{"request": {"type": "IntentRequest", "intent": {"name": "StateIntent", "slots": {
+		"State": {"name": "State", "value": "buckeye state", "resolutions": {"resolutionsPerAuthority": [
+			{"authority": "amzn1.er-authority.echo-sdk.test.US_STATE", "status": {"code": "ER_SUCCESS_MATCH"},
+			 "values": [{"value": {"name": "Ohio", "id": "OH"}}]}]}}}}}}`), &envelope))
+
+	slot := envelope.Request.Intent.Slots["State"]
+	// The field keeps the decoded JSON for existing callers
+	_, generic := slot.Resolutions.(map[string]interface{})
+	require.True(t, generic)
+	require.Equal(t, "amzn1.er-authority.echo-sdk.test.US_STATE", slot.GetResolutions().ResolutionsPerAuthority[0].Authority)
+	require.Equal(t, "OH", slot.FirstResolvedValue().ID)
+}
@@ -0,0 +1,92 @@
+package askgo
+
+// HandlerFunc is the function form of RequestHandler.Handle
+type HandlerFunc func(input HandlerInput) (*ResponseEnvelope, error)
+
+// IntentLister is implemented by request handlers that can report the intents they handle,
+// it is used to check the handlers of a skill against the interaction model.
+type IntentLister interface {
+	HandledIntents() []string
+}
+
+// Route is a RequestHandler that matches requests by type and intent name, most handlers
+// of a skill can be declared as a Route rather than implementing CanHandle by hand.
+//
+//	skill.Handlers = append(skill.Handlers, &askgo.Route{
+//		Intents: []string{"QuizIntent", alexa.StartOverIntent},
+//		Handler: startQuiz,
+//	})
+type Route struct {
+	// RequestType to match (e.g. "LaunchRequest"), when Intents are given it defaults to "IntentRequest"
+	RequestType string
+	// Intents handled by this route, if empty any intent matches
+	Intents []string
+	// Predicate is an optional extra condition for the route to match
+	Predicate func(input HandlerInput) bool
+	// Handler is called for matching requests
+	Handler HandlerFunc
+	// Middleware wraps the Handler, inside the middleware of the skill
+	Middleware []Middleware
+	// Name of the route in metrics, defaults to the name of the Handler function
+	Name string
+}
+
+var _ RequestHandler = &Route{}
+var _ IntentLister = &Route{}
+var _ HandlerNamer = &Route{}
+
+// IntentRoute returns a route for the given intents
+func IntentRoute(handler HandlerFunc, intents ...string) *Route {
+	return &Route{Intents: intents, Handler: handler}
+}
+
+// RequestRoute returns a route for the given request type (e.g. "SessionEndedRequest")
+func RequestRoute(handler HandlerFunc, requestType string) *Route {
+	return &Route{RequestType: requestType, Handler: handler}
+}
+
+// CanHandle matches the request type, intent name and predicate of the route
+func (route *Route) CanHandle(input HandlerInput) bool {
+	request := input.GetRequest()
+
+	requestType := route.RequestType
+	if requestType == "" && len(route.Intents) != 0 {
+		requestType = "IntentRequest"
+	}
+	if requestType != "" && request.Type != requestType {
+		return false
+	}
+
+	if len(route.Intents) != 0 {
+		found := false
+		for _, name := range route.Intents {
+			if name == request.Intent.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return route.Predicate == nil || route.Predicate(input)
+}
+
+// Handle calls the handler of the route through its middleware
+func (route *Route) Handle(input HandlerInput) (*ResponseEnvelope, error) {
+	return Chain(route.Handler, route.Middleware...)(input)
+}
+
+// HandledIntents returns the intents of the route
+func (route *Route) HandledIntents() []string {
+	return route.Intents
+}
+
+// HandlerName returns the name of the route or of its handler function
+func (route *Route) HandlerName() string {
+	if route.Name != "" {
+		return route.Name
+	}
+	return funcName(route.Handler)
+}
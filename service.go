@@ -0,0 +1,141 @@
+package askgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/koblas/askgo/alexa"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceClient calls the Alexa service APIs (e.g. progressive responses or the customer
+// profile) with the API endpoint and access token of a request.
+type ServiceClient struct {
+	// Endpoint is the base URL of the API (e.g. https://api.amazonalexa.com)
+	Endpoint string
+	// Token is sent as the bearer token
+	Token string
+	// HTTPClient defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// ServiceError is returned when an Alexa service API call does not succeed
+type ServiceError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (err *ServiceError) Error() string {
+	return fmt.Sprintf("askgo: %s %s: status %d: %s", err.Method, err.URL, err.StatusCode, strings.TrimSpace(err.Body))
+}
+
+// NewServiceClient returns a client for the API endpoint and access token of the request
+func NewServiceClient(input HandlerInput) *ServiceClient {
+	system := input.GetRequestEnvelope().Context.System
+	return &ServiceClient{Endpoint: system.APIEndpoint, Token: system.APIAccessToken}
+}
+
+// Do sends the body as JSON to the path of the API and decodes the JSON response into
+// result, either may be nil.  The call is traced as a child of the span of the context.
+func (client *ServiceClient) Do(ctx context.Context, method, path string, body, result interface{}) error {
+	url := strings.TrimSuffix(client.Endpoint, "/") + path
+
+	ctx, span := tracerFromContext(ctx).Start(ctx, SpanServiceCall, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("http.method", method), attribute.String("http.url", url)))
+	defer span.End()
+
+	err := client.do(ctx, span, method, url, body, result)
+	recordError(span, err)
+	return err
+}
+
+func (client *ServiceClient) do(ctx context.Context, span trace.Span, method, url string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+client.Token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := client.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &ServiceError{Method: method, URL: url, StatusCode: resp.StatusCode, Body: string(data)}
+	}
+
+	if result != nil && len(data) != 0 {
+		return json.Unmarshal(data, result)
+	}
+	return nil
+}
+
+// SendProgressiveResponse has Alexa speak to the user while the request is still being processed
+func (client *ServiceClient) SendProgressiveResponse(ctx context.Context, requestID, speech string) error {
+	body := map[string]interface{}{
+		"header":    map[string]string{"requestId": requestID},
+		"directive": map[string]string{"type": "VoicePlayer.Speak", "speech": speech},
+	}
+	return client.Do(ctx, "POST", "/v1/directives", body, nil)
+}
+
+// GetEndpoints returns the gadgets connected to the device of the request
+func (client *ServiceClient) GetEndpoints(ctx context.Context) ([]alexa.Endpoint, error) {
+	var result struct {
+		Endpoints []alexa.Endpoint `json:"endpoints"`
+	}
+	if err := client.Do(ctx, "GET", "/v1/endpoints", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Endpoints, nil
+}
+
+// GetAccountGivenName returns the given name of the account holder, it needs the
+// alexa::profile:given_name:read permission
+func (client *ServiceClient) GetAccountGivenName(ctx context.Context) (string, error) {
+	var name string
+	err := client.Do(ctx, "GET", "/v2/accounts/~current/settings/Profile.givenName", nil, &name)
+	return name, err
+}
+
+// GetPersonGivenName returns the given name of the person recognized by voice, it needs the
+// alexa::profile:given_name:read permission
+func (client *ServiceClient) GetPersonGivenName(ctx context.Context) (string, error) {
+	var name string
+	err := client.Do(ctx, "GET", "/v2/persons/~current/profile/givenName", nil, &name)
+	return name, err
+}
@@ -0,0 +1,40 @@
+package askgo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetEndpoints(t *testing.T) {
+	paths := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"endpoints": [{"endpointId": "gadget-1", "friendlyName": "Robot",
+			"capabilities": [{"type": "AlexaInterface", "interface": "Custom.Robot", "version": "1.0"}]}]}`))
+	}))
+	defer server.Close()
+
+	client := &askgo.ServiceClient{Endpoint: server.URL, Token: "token"}
+	endpoints, err := client.GetEndpoints(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "/v1/endpoints", <-paths)
+	require.Len(t, endpoints, 1)
+	require.Equal(t, "gadget-1", endpoints[0].EndpointID)
+	require.Equal(t, "Custom.Robot", endpoints[0].Capabilities[0].Interface)
+
+	client.Token = "expired"
+	_, err = client.GetEndpoints(context.Background())
+	var serviceErr *askgo.ServiceError
+	require.True(t, errors.As(err, &serviceErr))
+	require.Equal(t, http.StatusUnauthorized, serviceErr.StatusCode)
+}
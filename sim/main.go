@@ -0,0 +1,65 @@
+// Package sim is an interactive simulator for skills built with askgo.
+//
+// Utterances typed at the prompt are resolved with the interaction model into intents
+// and sent to the skill, the speech, reprompt and card of the response are printed and
+// the session is carried over to the next turn.  Commands start with a slash:
+//
+//	/launch                          send a LaunchRequest
+//	/intent Name Slot=value ...      send an IntentRequest, quote values with spaces
+//	/end [reason]                    send a SessionEndedRequest
+//	/locale en-GB                    change the locale
+//	/user ID                         change the user ID (starts a new session)
+//	/device display,audio,video,apl  set the supported interfaces of the device
+//	/viewport 1024x600@160 [round]   set the viewport of the device, /viewport none to remove it
+//	/attributes                      print the session attributes
+//	/reset                           start a new session
+//	/quit                            leave the simulator
+//
+// The simulator is only built with the askgosim tag, which the askgo-sim command sets, so
+// that production binaries calling Main do not link the test helpers it uses.
+package sim
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/koblas/askgo"
+)
+
+// EnvVar is set by the askgo-sim command to the JSON array of simulator arguments
+const EnvVar = "ASKGO_SIM"
+
+// Enabled reports whether Main will run the simulator, that is whether the program was
+// built with the askgosim tag
+func Enabled() bool {
+	return buildTag
+}
+
+// Main runs the simulator and exits when it is enabled, otherwise it returns immediately.
+// Call it from main before starting the Lambda handler:
+//
+//	func main() {
+//		skill := newSkill()
+//		sim.Main(skill)
+//		lambda.StartHandler(skill)
+//	}
+func Main(skill *askgo.Skill) {
+	if !Enabled() {
+		return
+	}
+
+	args := os.Args[1:]
+	if value := os.Getenv(EnvVar); value != "" && value != "1" {
+		if err := json.Unmarshal([]byte(value), &args); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", EnvVar, err)
+			os.Exit(2)
+		}
+	}
+
+	if err := start(skill, args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
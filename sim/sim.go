@@ -0,0 +1,254 @@
+//go:build askgosim
+// +build askgosim
+
+package sim
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/koblas/askgo/askgotest"
+)
+
+// Simulator is a read-eval-print loop around a conversation with a skill
+type Simulator struct {
+	Conversation *askgotest.Conversation
+	In           io.Reader
+	Out          io.Writer
+	// Prompt is printed before reading each line
+	Prompt string
+}
+
+var errQuit = errors.New("quit")
+
+// Interfaces that can be enabled with /device
+var deviceInterfaces = map[string]struct {
+	name  string
+	value interface{}
+}{
+	"audio":   {"AudioPlayer", map[string]interface{}{}},
+	"display": {"Display", map[string]interface{}{"templateVersion": "1.0", "markupVersion": "1.0"}},
+	"video":   {"VideoApp", map[string]interface{}{}},
+	"apl":     {"Alexa.Presentation.APL", map[string]interface{}{"runtime": map[string]interface{}{"maxVersion": "1.1"}}},
+}
+
+// Run reads lines until the input is exhausted or /quit is entered
+func (sim *Simulator) Run() error {
+	scanner := bufio.NewScanner(sim.In)
+
+	for {
+		if sim.Prompt != "" {
+			fmt.Fprint(sim.Out, sim.Prompt)
+		}
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := sim.Execute(line); err == errQuit {
+			return nil
+		} else if err != nil {
+			fmt.Fprintf(sim.Out, "error: %v\n", err)
+		}
+	}
+}
+
+// Execute runs a single command or utterance
+func (sim *Simulator) Execute(line string) error {
+	conv := sim.Conversation
+
+	if !strings.HasPrefix(line, "/") {
+		return sim.print(conv.Say(line))
+	}
+
+	args, err := splitArgs(line[1:])
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New("missing command")
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "launch":
+		return sim.print(conv.Launch())
+	case "intent":
+		if len(args) == 0 {
+			return errors.New("usage: /intent Name Slot=value ...")
+		}
+		slots := make(map[string]string)
+		for _, arg := range args[1:] {
+			parts := strings.SplitN(arg, "=", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("slot %q must be Name=value", arg)
+			}
+			slots[parts[0]] = parts[1]
+		}
+		return sim.print(conv.Intent(args[0], slots))
+	case "end":
+		reason := "USER_INITIATED"
+		if len(args) != 0 {
+			reason = args[0]
+		}
+		return sim.print(conv.End(reason))
+	case "locale":
+		if len(args) != 1 {
+			return errors.New("usage: /locale en-US")
+		}
+		conv.Locale = args[0]
+	case "user":
+		if len(args) != 1 {
+			return errors.New("usage: /user ID")
+		}
+		conv.UserID = args[0]
+		conv.Reset()
+	case "device":
+		interfaces, err := ParseDevice(strings.Join(args, ","))
+		if err != nil {
+			return err
+		}
+		conv.SupportedInterfaces = interfaces
+	case "viewport":
+		viewport, err := ParseViewport(strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		conv.Viewport = viewport
+		if viewport != nil {
+			fmt.Fprintf(sim.Out, "viewport profile %s\n", askgo.ClassifyViewport(viewport))
+		}
+	case "attributes":
+		data, _ := json.MarshalIndent(conv.SessionAttributes(), "", "  ")
+		fmt.Fprintln(sim.Out, string(data))
+	case "reset":
+		conv.Reset()
+	case "quit", "exit":
+		return errQuit
+	case "help":
+		fmt.Fprintln(sim.Out, "commands: /launch /intent /end /locale /user /device /viewport /attributes /reset /quit")
+	default:
+		return fmt.Errorf("unknown command /%s", command)
+	}
+
+	return nil
+}
+
+// print writes the response as the user would experience it
+func (sim *Simulator) print(response *askgo.ResponseEnvelope, err error) error {
+	if err != nil {
+		return err
+	}
+	if response == nil {
+		fmt.Fprintln(sim.Out, "(no response)")
+		return nil
+	}
+
+	if speech := askgotest.Speech(response); speech != "" {
+		fmt.Fprintf(sim.Out, "Alexa: %s\n", speech)
+	}
+	if reprompt := askgotest.Reprompt(response); reprompt != "" {
+		fmt.Fprintf(sim.Out, "  reprompt: %s\n", reprompt)
+	}
+	if body := response.Response; body != nil && body.Card != nil {
+		fmt.Fprintf(sim.Out, "  card (%s): %s\n", body.Card.Type, body.Card.Title)
+		if text := body.Card.Content + body.Card.Text; text != "" {
+			fmt.Fprintf(sim.Out, "    %s\n", strings.Replace(text, "\n", "\n    ", -1))
+		}
+	}
+	if types := askgotest.DirectiveTypes(response); len(types) != 0 {
+		fmt.Fprintf(sim.Out, "  directives: %s\n", strings.Join(types, ", "))
+	}
+	if !sim.Conversation.InSession() {
+		fmt.Fprintln(sim.Out, "  (session ended)")
+	}
+
+	return nil
+}
+
+// ParseDevice converts a comma separated list of capabilities (audio, display, video, apl)
+// into the supported interfaces of a device
+func ParseDevice(spec string) (map[string]interface{}, error) {
+	interfaces := make(map[string]interface{})
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || name == "none" {
+			continue
+		}
+		iface, found := deviceInterfaces[name]
+		if !found {
+			known := make([]string, 0, len(deviceInterfaces))
+			for k := range deviceInterfaces {
+				known = append(known, k)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("unknown capability %q, expected one of %s", name, strings.Join(known, ", "))
+		}
+		interfaces[iface.name] = iface.value
+	}
+	return interfaces, nil
+}
+
+// ParseViewport converts WIDTHxHEIGHT[@DPI] [round] into a viewport, "none" or "" returns nil
+func ParseViewport(spec string) (*alexa.Viewport, error) {
+	fields := strings.Fields(strings.ToLower(spec))
+	if len(fields) == 0 || fields[0] == "none" {
+		return nil, nil
+	}
+
+	viewport := &alexa.Viewport{Shape: "RECTANGLE", DPI: 160}
+	if _, err := fmt.Sscanf(fields[0], "%dx%d@%d", &viewport.PixelWidth, &viewport.PixelHeight, &viewport.DPI); err != nil {
+		if _, err := fmt.Sscanf(fields[0], "%dx%d", &viewport.PixelWidth, &viewport.PixelHeight); err != nil {
+			return nil, fmt.Errorf("viewport %q must be WIDTHxHEIGHT[@DPI]", fields[0])
+		}
+	}
+	if len(fields) > 1 && fields[1] == "round" {
+		viewport.Shape = "ROUND"
+	}
+	viewport.CurrentPixelWidth = viewport.PixelWidth
+	viewport.CurrentPixelHeight = viewport.PixelHeight
+
+	return viewport, nil
+}
+
+// splitArgs splits a line on whitespace, double quotes group words
+func splitArgs(line string) ([]string, error) {
+	args := make([]string, 0)
+	var current strings.Builder
+	inQuote, hasToken := false, false
+
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			hasToken = true
+		case !inQuote && (r == ' ' || r == '\t'):
+			if hasToken {
+				args = append(args, current.String())
+				current.Reset()
+				hasToken = false
+			}
+		default:
+			current.WriteRune(r)
+			hasToken = true
+		}
+	}
+	if inQuote {
+		return nil, errors.New("unterminated quote")
+	}
+	if hasToken {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
@@ -0,0 +1,81 @@
+//go:build askgosim
+// +build askgosim
+
+package sim_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/koblas/askgo/sim"
+	"github.com/stretchr/testify/require"
+)
+
+func greeterSkill() *askgo.Skill {
+	return &askgo.Skill{
+		Handlers: []askgo.RequestHandler{
+			askgo.RequestRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				return input.GetResponse().Speak("Who should I greet?").Reprompt("Say a name."), nil
+			}, "LaunchRequest"),
+			askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				name := input.GetRequest().Intent.Slots["Name"].Value
+				if name == "" {
+					name = "world"
+				}
+				response := input.GetResponse().Speak("Hello "+name).WithSimpleCard("Greeting", "Hello "+name)
+				response.SessionAttributes = map[string]interface{}{"last": name, "locale": input.GetRequest().Locale}
+				return response, nil
+			}, "HelloIntent"),
+			askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				return input.GetResponse().Speak("Goodbye").WithShouldEndSession(true), nil
+			}, alexa.StopIntent),
+		},
+	}
+}
+
+func Test_Simulator(t *testing.T) {
+	in := strings.NewReader(strings.Join([]string{
+		"/launch",
+		"Say hello to Ada!",
+		`/intent HelloIntent Name="Grace Hopper"`,
+		"/locale en-GB",
+		"hello",
+		"/attributes",
+		"stop",
+		"/bogus",
+		"/quit",
+		"hello",
+	}, "\n"))
+	out := &bytes.Buffer{}
+
+	err := sim.Start(greeterSkill(), []string{"-model", "testdata/model.json", "-device", "display"}, in, out)
+	require.NoError(t, err)
+
+	output := out.String()
+	require.Contains(t, output, "Alexa: Who should I greet?\n  reprompt: Say a name.\n")
+	require.Contains(t, output, "Alexa: Hello ada\n  card (Simple): Greeting\n    Hello ada\n")
+	require.Contains(t, output, "Alexa: Hello Grace Hopper\n")
+	require.Contains(t, output, `"locale": "en-GB"`)
+	require.Contains(t, output, "Alexa: Goodbye\n  (session ended)\n")
+	require.Contains(t, output, "error: unknown command /bogus\n")
+	require.Equal(t, 3, strings.Count(output, "Alexa: Hello"))
+}
+
+func Test_ParseViewport(t *testing.T) {
+	viewport, err := sim.ParseViewport("480x480@160 round")
+	require.NoError(t, err)
+	require.Equal(t, askgo.ViewportProfileHubRoundSmall, askgo.ClassifyViewport(viewport))
+
+	viewport, err = sim.ParseViewport("none")
+	require.NoError(t, err)
+	require.Nil(t, viewport)
+
+	_, err = sim.ParseViewport("big")
+	require.Error(t, err)
+
+	_, err = sim.ParseDevice("display,hologram")
+	require.Error(t, err)
+}
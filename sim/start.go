@@ -0,0 +1,109 @@
+//go:build askgosim
+// +build askgosim
+
+package sim
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/askgotest"
+	"github.com/koblas/askgo/model"
+	"github.com/koblas/askgo/record"
+)
+
+// Start parses the simulator arguments and runs the simulator until the input is exhausted
+func Start(skill *askgo.Skill, args []string, in io.Reader, out io.Writer) error {
+	flags := flag.NewFlagSet("askgo-sim", flag.ContinueOnError)
+	flags.SetOutput(out)
+	locale := flags.String("locale", "en-US", "locale of the requests")
+	modelFile := flags.String("model", "", "interaction model JSON used to resolve utterances")
+	user := flags.String("user", "", "user ID of the requests")
+	device := flags.String("device", "", "device capabilities (audio, display, video, apl)")
+	viewport := flags.String("viewport", "", "device viewport as WIDTHxHEIGHT[@DPI]")
+	replay := flags.String("replay", "", "replay the recorded requests of a file or directory rather than starting the simulator")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *replay != "" {
+		return Replay(skill, *replay, out)
+	}
+
+	conv := askgotest.NewConversation(skill)
+	conv.Locale = *locale
+	if *user != "" {
+		conv.UserID = *user
+	}
+	if *device != "" {
+		interfaces, err := ParseDevice(*device)
+		if err != nil {
+			return err
+		}
+		conv.SupportedInterfaces = interfaces
+	}
+	if *viewport != "" {
+		value, err := ParseViewport(*viewport)
+		if err != nil {
+			return err
+		}
+		conv.Viewport = value
+	}
+	if *modelFile != "" {
+		schema, err := model.LoadFile(*modelFile)
+		if err != nil {
+			return err
+		}
+		matcher, err := model.NewMatcher(schema)
+		if err != nil {
+			return err
+		}
+		matcher.ApplicationID = skill.ApplicationID
+		conv.Matcher = matcher
+	}
+
+	simulator := &Simulator{Conversation: conv, In: in, Out: out}
+	if in == os.Stdin {
+		simulator.Prompt = "> "
+	}
+
+	return simulator.Run()
+}
+
+// Replay replays the records of the file or directory through the skill, printing the
+// difference for every response that changed
+func Replay(skill *askgo.Skill, path string, out io.Writer) error {
+	records, err := record.Load(path)
+	if err != nil {
+		return err
+	}
+
+	changed := 0
+	for _, result := range record.ReplayAll(skill, records) {
+		request := result.Record.Request.Request
+		name := request.Type
+		if request.Intent.Name != "" {
+			name += " " + request.Intent.Name
+		}
+
+		switch {
+		case !result.Changed():
+			fmt.Fprintf(out, "OK   %s %s\n", request.RequestID, name)
+		case result.Err != nil:
+			fmt.Fprintf(out, "FAIL %s %s: %v\n", request.RequestID, name, result.Err)
+		default:
+			fmt.Fprintf(out, "DIFF %s %s\n%s", request.RequestID, name, result.Diff)
+		}
+		if result.Changed() {
+			changed++
+		}
+	}
+
+	if changed != 0 {
+		return fmt.Errorf("%d of %d replayed responses changed", changed, len(records))
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build askgosim
+// +build askgosim
+
+package sim
+
+import (
+	"os"
+
+	"github.com/koblas/askgo"
+)
+
+// Programs built with -tags askgosim always start the simulator from Main
+const buildTag = true
+
+func start(skill *askgo.Skill, args []string) error {
+	return Start(skill, args, os.Stdin, os.Stdout)
+}
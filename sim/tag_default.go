@@ -0,0 +1,13 @@
+//go:build !askgosim
+// +build !askgosim
+
+package sim
+
+import "github.com/koblas/askgo"
+
+const buildTag = false
+
+// start is never called, Main returns first when the simulator is not built in
+func start(skill *askgo.Skill, args []string) error {
+	return nil
+}
@@ -0,0 +1,71 @@
+package smarthome
+
+// DiscoveryEndpoint describes an endpoint and its capabilities in the response to Discover
+type DiscoveryEndpoint struct {
+	EndpointID        string            `json:"endpointId"`
+	ManufacturerName  string            `json:"manufacturerName"`
+	FriendlyName      string            `json:"friendlyName"`
+	Description       string            `json:"description"`
+	DisplayCategories []string          `json:"displayCategories"`
+	Cookie            map[string]string `json:"cookie,omitempty"`
+	Capabilities      []Capability      `json:"capabilities"`
+}
+
+// Capability is an interface supported by an endpoint
+type Capability struct {
+	Type       string                `json:"type"`
+	Interface  string                `json:"interface"`
+	Version    string                `json:"version"`
+	Properties *CapabilityProperties `json:"properties,omitempty"`
+	// Configuration is specific to the interface (e.g. the supported modes of a thermostat)
+	Configuration interface{} `json:"configuration,omitempty"`
+}
+
+// CapabilityProperties are the properties of an interface the endpoint reports
+type CapabilityProperties struct {
+	Supported           []SupportedProperty `json:"supported"`
+	ProactivelyReported bool                `json:"proactivelyReported"`
+	Retrievable         bool                `json:"retrievable"`
+}
+
+// SupportedProperty names a property of an interface
+type SupportedProperty struct {
+	Name string `json:"name"`
+}
+
+// NewCapability returns a capability for the interface whose properties can be retrieved with
+// ReportState and are reported to the event gateway
+func NewCapability(iface string, properties ...string) Capability {
+	capability := Capability{Type: "AlexaInterface", Interface: iface, Version: PayloadVersion}
+	if len(properties) != 0 {
+		supported := make([]SupportedProperty, 0, len(properties))
+		for _, name := range properties {
+			supported = append(supported, SupportedProperty{Name: name})
+		}
+		capability.Properties = &CapabilityProperties{Supported: supported, ProactivelyReported: true, Retrievable: true}
+	}
+	return capability
+}
+
+// NewDiscoveryResponse answers Discover with the endpoints of the user, the Alexa interface is
+// added to the capabilities of every endpoint
+func NewDiscoveryResponse(input HandlerInput, endpoints ...DiscoveryEndpoint) *ResponseEnvelope {
+	for idx := range endpoints {
+		endpoint := &endpoints[idx]
+		hasAlexa := false
+		for _, capability := range endpoint.Capabilities {
+			hasAlexa = hasAlexa || capability.Interface == Alexa
+		}
+		if !hasAlexa {
+			endpoint.Capabilities = append([]Capability{NewCapability(Alexa)}, endpoint.Capabilities...)
+		}
+	}
+	if endpoints == nil {
+		endpoints = []DiscoveryEndpoint{}
+	}
+
+	return &ResponseEnvelope{Event: Event{
+		Header:  newHeader(Discovery, "Discover.Response", input.GetDirective().Header.CorrelationToken),
+		Payload: map[string]interface{}{"endpoints": endpoints},
+	}}
+}
@@ -0,0 +1,58 @@
+package smarthome
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/koblas/askgo"
+)
+
+// Event gateways by region
+const (
+	NorthAmerica = "https://api.amazonalexa.com"
+	Europe       = "https://api.eu.amazonalexa.com"
+	FarEast      = "https://api.fe.amazonalexa.com"
+)
+
+// EventsPath is the path of the event gateway
+const EventsPath = "/v3/events"
+
+// Causes of a ChangeReport
+const (
+	CauseAppInteraction      = "APP_INTERACTION"
+	CausePeriodicPoll        = "PERIODIC_POLL"
+	CausePhysicalInteraction = "PHYSICAL_INTERACTION"
+	CauseRuleTrigger         = "RULE_TRIGGER"
+	CauseVoiceInteraction    = "VOICE_INTERACTION"
+)
+
+// NewChangeReport reports the properties of the endpoint that changed, the properties that did
+// not change are added to the context with WithProperty
+func NewChangeReport(endpointID, token, cause string, changed ...Property) *ResponseEnvelope {
+	return &ResponseEnvelope{Event: Event{
+		Header:   newHeader(Alexa, "ChangeReport", ""),
+		Endpoint: &Endpoint{EndpointID: endpointID, Scope: &Scope{Type: "BearerToken", Token: token}},
+		Payload: map[string]interface{}{"change": map[string]interface{}{
+			"cause":      map[string]string{"type": cause},
+			"properties": changed,
+		}},
+	}}
+}
+
+// Gateway sends events, such as ChangeReport, to the Alexa event gateway
+type Gateway struct {
+	// Endpoint is the event gateway of the region of the user, defaults to NorthAmerica
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+// Send posts the event with the access token of the user obtained in Alexa.Authorization AcceptGrant
+func (gateway *Gateway) Send(ctx context.Context, token string, event *ResponseEnvelope) error {
+	endpoint := gateway.Endpoint
+	if endpoint == "" {
+		endpoint = NorthAmerica
+	}
+
+	service := &askgo.ServiceClient{Endpoint: endpoint, Token: token, HTTPClient: gateway.HTTPClient}
+	return service.Do(ctx, "POST", EventsPath, event, nil)
+}
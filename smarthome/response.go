@@ -0,0 +1,138 @@
+package smarthome
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Types of Alexa.ErrorResponse
+const (
+	ErrorBridgeUnreachable              = "BRIDGE_UNREACHABLE"
+	ErrorEndpointBusy                   = "ENDPOINT_BUSY"
+	ErrorEndpointLowPower               = "ENDPOINT_LOW_POWER"
+	ErrorEndpointUnreachable            = "ENDPOINT_UNREACHABLE"
+	ErrorExpiredAuthorizationCredential = "EXPIRED_AUTHORIZATION_CREDENTIAL"
+	ErrorFirmwareOutOfDate              = "FIRMWARE_OUT_OF_DATE"
+	ErrorHardwareMalfunction            = "HARDWARE_MALFUNCTION"
+	ErrorInternal                       = "INTERNAL_ERROR"
+	ErrorInvalidAuthorizationCredential = "INVALID_AUTHORIZATION_CREDENTIAL"
+	ErrorInvalidDirective               = "INVALID_DIRECTIVE"
+	ErrorInvalidValue                   = "INVALID_VALUE"
+	ErrorNoSuchEndpoint                 = "NO_SUCH_ENDPOINT"
+	ErrorNotSupportedInCurrentMode      = "NOT_SUPPORTED_IN_CURRENT_MODE"
+	ErrorRateLimitExceeded              = "RATE_LIMIT_EXCEEDED"
+	ErrorTemperatureValueOutOfRange     = "TEMPERATURE_VALUE_OUT_OF_RANGE"
+	ErrorValueOutOfRange                = "VALUE_OUT_OF_RANGE"
+)
+
+// Error is returned by handlers to answer with an Alexa.ErrorResponse
+type Error struct {
+	// Type is one of the Error* constants
+	Type    string
+	Message string
+	// Payload holds the fields specific to the type (e.g. "validRange" of VALUE_OUT_OF_RANGE)
+	Payload map[string]interface{}
+}
+
+func (err *Error) Error() string {
+	return "smarthome: " + err.Type + ": " + err.Message
+}
+
+// NewValueOutOfRangeError reports a value outside of the range supported by the endpoint
+func NewValueOutOfRangeError(message string, minimum, maximum interface{}) *Error {
+	return &Error{Type: ErrorValueOutOfRange, Message: message, Payload: map[string]interface{}{
+		"validRange": map[string]interface{}{"minimumValue": minimum, "maximumValue": maximum},
+	}}
+}
+
+func newMessageID() string {
+	data := make([]byte, 16)
+	rand.Read(data)
+	return hex.EncodeToString(data)
+}
+
+func newHeader(namespace, name, correlationToken string) Header {
+	return Header{
+		Namespace:        namespace,
+		Name:             name,
+		PayloadVersion:   PayloadVersion,
+		MessageID:        newMessageID(),
+		CorrelationToken: correlationToken,
+	}
+}
+
+func newEvent(input HandlerInput, namespace, name string, payload interface{}) *ResponseEnvelope {
+	directive := input.GetDirective()
+	return &ResponseEnvelope{Event: Event{
+		Header:   newHeader(namespace, name, directive.Header.CorrelationToken),
+		Endpoint: directive.Endpoint,
+		Payload:  payload,
+	}}
+}
+
+// NewResponse acknowledges a directive for the endpoint, the new state of the endpoint is added
+// with WithProperty
+func NewResponse(input HandlerInput) *ResponseEnvelope {
+	return newEvent(input, Alexa, "Response", map[string]interface{}{})
+}
+
+// NewStateReport answers ReportState, the state of the endpoint is added with WithProperty
+func NewStateReport(input HandlerInput) *ResponseEnvelope {
+	return newEvent(input, Alexa, "StateReport", map[string]interface{}{})
+}
+
+// NewErrorResponse answers a directive with an error
+func NewErrorResponse(input HandlerInput, err *Error) *ResponseEnvelope {
+	payload := map[string]interface{}{"type": err.Type, "message": err.Message}
+	for key, value := range err.Payload {
+		payload[key] = value
+	}
+	return newEvent(input, Alexa, "ErrorResponse", payload)
+}
+
+// WithProperty adds a property of the state of the endpoint to the context
+func (response *ResponseEnvelope) WithProperty(property Property) *ResponseEnvelope {
+	if response.Context == nil {
+		response.Context = &Context{}
+	}
+	response.Context.Properties = append(response.Context.Properties, property)
+	return response
+}
+
+// WithPowerState adds the Alexa.PowerController powerState property, "ON" or "OFF"
+func (response *ResponseEnvelope) WithPowerState(on bool) *ResponseEnvelope {
+	value := "OFF"
+	if on {
+		value = "ON"
+	}
+	return response.WithProperty(NewProperty(PowerController, "powerState", value))
+}
+
+// WithBrightness adds the Alexa.BrightnessController brightness property (0 to 100)
+func (response *ResponseEnvelope) WithBrightness(brightness int) *ResponseEnvelope {
+	return response.WithProperty(NewProperty(BrightnessController, "brightness", brightness))
+}
+
+// WithTargetSetpoint adds the Alexa.ThermostatController targetSetpoint property
+func (response *ResponseEnvelope) WithTargetSetpoint(temperature Temperature) *ResponseEnvelope {
+	return response.WithProperty(NewProperty(ThermostatController, "targetSetpoint", temperature))
+}
+
+// WithThermostatMode adds the Alexa.ThermostatController thermostatMode property
+func (response *ResponseEnvelope) WithThermostatMode(mode string) *ResponseEnvelope {
+	return response.WithProperty(NewProperty(ThermostatController, "thermostatMode", mode))
+}
+
+// WithTemperature adds the Alexa.TemperatureSensor temperature property
+func (response *ResponseEnvelope) WithTemperature(temperature Temperature) *ResponseEnvelope {
+	return response.WithProperty(NewProperty(TemperatureSensor, "temperature", temperature))
+}
+
+// WithConnectivity adds the Alexa.EndpointHealth connectivity property
+func (response *ResponseEnvelope) WithConnectivity(reachable bool) *ResponseEnvelope {
+	value := "UNREACHABLE"
+	if reachable {
+		value = "OK"
+	}
+	return response.WithProperty(NewProperty(EndpointHealth, "connectivity", map[string]string{"value": value}))
+}
@@ -0,0 +1,303 @@
+package smarthome
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/koblas/askgo"
+)
+
+// HandlerInput is passed to the handlers and interceptors of a directive
+type HandlerInput interface {
+	// GetDirective returns the directive being handled
+	GetDirective() Directive
+	// DecodePayload decodes the payload of the directive (e.g. into a SetBrightnessPayload)
+	DecodePayload(payload interface{}) error
+	// GetContext returns the context of the directive
+	GetContext() context.Context
+	// SetContext replaces the context of the directive
+	SetContext(ctx context.Context)
+	// GetLogger returns the logger for the directive
+	GetLogger() askgo.Logger
+}
+
+// HandlerFunc answers a directive
+type HandlerFunc func(input HandlerInput) (*ResponseEnvelope, error)
+
+// RequestHandler handles one or more directives
+type RequestHandler interface {
+	CanHandle(input HandlerInput) bool
+	Handle(input HandlerInput) (*ResponseEnvelope, error)
+}
+
+// ErrorHandler answers the errors returned by the request handlers and interceptors
+type ErrorHandler interface {
+	CanHandle(input HandlerInput, err error) bool
+	Handle(input HandlerInput, err error) (*ResponseEnvelope, error)
+}
+
+// RequestInterceptor is called before the request handler
+type RequestInterceptor interface {
+	Process(input HandlerInput) error
+}
+
+// ResponseInterceptor is called after the request handler
+type ResponseInterceptor interface {
+	Process(input HandlerInput, response *ResponseEnvelope) error
+}
+
+// Middleware wraps the handling of a directive, as askgo.Middleware does for a skill
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Route handles the directives of a namespace, or a single directive when Name is set
+type Route struct {
+	Namespace string
+	Name      string
+	Handler   HandlerFunc
+}
+
+var _ RequestHandler = &Route{}
+
+// CanHandle matches the namespace and name of the directive
+func (route *Route) CanHandle(input HandlerInput) bool {
+	header := input.GetDirective().Header
+	return header.Namespace == route.Namespace && (route.Name == "" || header.Name == route.Name)
+}
+
+// Handle calls the handler of the route
+func (route *Route) Handle(input HandlerInput) (*ResponseEnvelope, error) {
+	return route.Handler(input)
+}
+
+// HandlerName names the route after its directive
+func (route *Route) HandlerName() string {
+	if route.Name == "" {
+		return route.Namespace
+	}
+	return route.Namespace + "." + route.Name
+}
+
+// Router dispatches the directives of a smart home skill to its handlers, an error is answered
+// with an Alexa.ErrorResponse when no error handler takes it
+type Router struct {
+	RequestInterceptors  []RequestInterceptor
+	Handlers             []RequestHandler
+	ResponseInterceptors []ResponseInterceptor
+	ErrorHandlers        []ErrorHandler
+	// FallbackHandler handles the directives no request handler can handle, when it is nil
+	// they fail with an askgo.NoHandlerError answered as INVALID_DIRECTIVE
+	FallbackHandler HandlerFunc
+	// Middleware wraps the interceptors and handler, the first one is outermost
+	Middleware []Middleware
+	// Logger receives the log entries of the router, the default uses slog.Default()
+	Logger askgo.Logger
+}
+
+// fallback adapts the FallbackHandler of the router to a RequestHandler
+type fallback HandlerFunc
+
+func (handler fallback) CanHandle(input HandlerInput) bool { return true }
+
+func (handler fallback) Handle(input HandlerInput) (*ResponseEnvelope, error) {
+	return handler(input)
+}
+
+func (handler fallback) HandlerName() string { return "fallback" }
+
+// DefaultHandler is the HandlerInput of a decoded directive envelope
+type DefaultHandler struct {
+	envelope *DirectiveEnvelope
+	context  context.Context
+}
+
+var _ HandlerInput = &DefaultHandler{}
+
+// NewDefaultHandler returns the HandlerInput of the envelope
+func NewDefaultHandler(ctx context.Context, envelope *DirectiveEnvelope) *DefaultHandler {
+	return &DefaultHandler{envelope: envelope, context: ctx}
+}
+
+// GetDirective returns the directive of the envelope
+func (handler *DefaultHandler) GetDirective() Directive {
+	return handler.envelope.Directive
+}
+
+// DecodePayload decodes the payload of the directive
+func (handler *DefaultHandler) DecodePayload(payload interface{}) error {
+	if len(handler.envelope.Directive.Payload) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(handler.envelope.Directive.Payload, payload); err != nil {
+		return &Error{Type: ErrorInvalidDirective, Message: err.Error()}
+	}
+	return nil
+}
+
+// GetContext returns the context of the directive
+func (handler *DefaultHandler) GetContext() context.Context {
+	return handler.context
+}
+
+// SetContext replaces the context of the directive
+func (handler *DefaultHandler) SetContext(ctx context.Context) {
+	handler.context = ctx
+}
+
+// GetLogger returns the logger of the context
+func (handler *DefaultHandler) GetLogger() askgo.Logger {
+	return askgo.LoggerFromContext(handler.context)
+}
+
+// Invoke decodes the directive envelope from the payload, processes it and returns the
+// encoded response.  It implements the lambda.Handler interface of aws-lambda-go.
+func (router *Router) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	envelope := &DirectiveEnvelope{}
+	if err := json.Unmarshal(payload, envelope); err != nil {
+		return nil, &askgo.DecodeError{Err: err}
+	}
+
+	response, err := router.ProcessDirective(NewDefaultHandler(ctx, envelope))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(response)
+}
+
+// ProcessDirective runs the interceptors and the first matching handler for the directive,
+// panics are recovered as an askgo.PanicError
+func (router *Router) ProcessDirective(input HandlerInput) (*ResponseEnvelope, error) {
+	if input.GetContext() == nil {
+		input.SetContext(context.Background())
+	}
+
+	header := input.GetDirective().Header
+	logger := router.Logger
+	if logger == nil {
+		logger = askgo.NewSlogLogger(nil)
+	}
+	logger = logger.With("namespace", header.Namespace, "name", header.Name, "message_id", header.MessageID)
+	if endpoint := input.GetDirective().Endpoint; endpoint != nil {
+		logger = logger.With("endpoint_id", endpoint.EndpointID)
+	}
+	input.SetContext(askgo.WithLogger(input.GetContext(), logger))
+
+	chain := askgo.ChainOf(HandlerFunc(router.handle), router.Middleware...)
+
+	var response *ResponseEnvelope
+	err := askgo.Capture(func() (err error) {
+		response, err = chain(input)
+		return err
+	})
+	if err != nil {
+		return router.dispatchError(input, err)
+	}
+	return response, nil
+}
+
+func (router *Router) handle(input HandlerInput) (*ResponseEnvelope, error) {
+	for _, interceptor := range router.RequestInterceptors {
+		if err := askgo.Capture(func() error { return interceptor.Process(input) }); err != nil {
+			return nil, &askgo.InterceptorError{Interceptor: askgo.HandlerName(interceptor), Phase: "request", Err: err}
+		}
+	}
+
+	handler, err := router.findHandler(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var response *ResponseEnvelope
+	err = askgo.Capture(func() (err error) {
+		response, err = handler.Handle(input)
+		return err
+	})
+	if err == nil && response == nil {
+		err = errors.New("smarthome: the handler returned no response")
+	}
+	if err != nil {
+		return nil, &askgo.HandlerError{Handler: askgo.HandlerName(handler), Err: err}
+	}
+
+	for _, interceptor := range router.ResponseInterceptors {
+		if err := askgo.Capture(func() error { return interceptor.Process(input, response) }); err != nil {
+			return nil, &askgo.InterceptorError{Interceptor: askgo.HandlerName(interceptor), Phase: "response", Err: err}
+		}
+	}
+	return response, nil
+}
+
+// findHandler returns the first handler that can handle the directive, or the fallback handler
+func (router *Router) findHandler(input HandlerInput) (RequestHandler, error) {
+	consulted := make([]string, 0, len(router.Handlers))
+	for _, handler := range router.Handlers {
+		name := askgo.HandlerName(handler)
+		consulted = append(consulted, name)
+
+		var matched bool
+		if err := askgo.Capture(func() error {
+			matched = handler.CanHandle(input)
+			return nil
+		}); err != nil {
+			return nil, &askgo.HandlerError{Handler: name, Err: err}
+		}
+		if matched {
+			return handler, nil
+		}
+	}
+
+	if router.FallbackHandler != nil {
+		return fallback(router.FallbackHandler), nil
+	}
+	header := input.GetDirective().Header
+	input.GetLogger().Debug("No request handler matched", "consulted", consulted)
+	return nil, &askgo.NoHandlerError{RequestType: header.Namespace, Intent: header.Name, Consulted: consulted}
+}
+
+// dispatchError gives the error to the first error handler that takes it, otherwise answers
+// with an error response
+func (router *Router) dispatchError(input HandlerInput, err error) (*ResponseEnvelope, error) {
+	var panicErr *askgo.PanicError
+	if errors.As(err, &panicErr) {
+		input.GetLogger().Error("Recovered from panic", "error", err, "stack", string(panicErr.Stack))
+	}
+
+	for _, handler := range router.ErrorHandlers {
+		var matched bool
+		if handlerErr := askgo.Capture(func() error {
+			matched = handler.CanHandle(input, err)
+			return nil
+		}); handlerErr != nil {
+			return nil, &askgo.ErrorHandlerError{Handler: askgo.HandlerName(handler), Err: handlerErr, Cause: err}
+		}
+		if !matched {
+			continue
+		}
+
+		var response *ResponseEnvelope
+		handlerErr := askgo.Capture(func() (handlerErr error) {
+			response, handlerErr = handler.Handle(input, err)
+			return handlerErr
+		})
+		if handlerErr != nil {
+			return nil, &askgo.ErrorHandlerError{Handler: askgo.HandlerName(handler), Err: handlerErr, Cause: err}
+		}
+		return response, nil
+	}
+
+	var shErr *Error
+	if errors.Is(err, askgo.ErrNoHandler) {
+		header := input.GetDirective().Header
+		input.GetLogger().Warn("Unsupported directive", "error", err)
+		shErr = &Error{Type: ErrorInvalidDirective, Message: fmt.Sprintf("unsupported directive %s.%s", header.Namespace, header.Name)}
+	} else if !errors.As(err, &shErr) {
+		if panicErr == nil {
+			input.GetLogger().Error("Directive failed", "error", err)
+		}
+		shErr = &Error{Type: ErrorInternal, Message: "internal error"}
+	} else {
+		input.GetLogger().Warn("Directive answered with an error", "type", shErr.Type, "error", err)
+	}
+	return NewErrorResponse(input, shErr), nil
+}
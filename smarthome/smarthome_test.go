@@ -0,0 +1,324 @@
+package smarthome_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/smarthome"
+	"github.com/stretchr/testify/require"
+)
+
+func directive(namespace, name, endpointID, payload string) []byte {
+	envelope := map[string]interface{}{"directive": map[string]interface{}{
+		"header": map[string]string{
+			"namespace": namespace, "name": name, "payloadVersion": "3",
+			"messageId": "message-1", "correlationToken": "correlation-1",
+		},
+		"endpoint": map[string]interface{}{
+			"scope":      map[string]string{"type": "BearerToken", "token": "access-token"},
+			"endpointId": endpointID,
+		},
+		"payload": json.RawMessage(payload),
+	}}
+	data, _ := json.Marshal(envelope)
+	return data
+}
+
+func invoke(t *testing.T, router *smarthome.Router, payload []byte) map[string]interface{} {
+	data, err := router.Invoke(context.Background(), payload)
+	require.NoError(t, err)
+	result := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(data, &result))
+	return result
+}
+
+func lampRouter() *smarthome.Router {
+	return &smarthome.Router{Handlers: []smarthome.RequestHandler{
+		&smarthome.Route{Namespace: smarthome.Discovery, Name: "Discover", Handler: func(input smarthome.HandlerInput) (*smarthome.ResponseEnvelope, error) {
+			return smarthome.NewDiscoveryResponse(input, smarthome.DiscoveryEndpoint{
+				EndpointID: "lamp", FriendlyName: "Lamp", DisplayCategories: []string{"LIGHT"},
+				Capabilities: []smarthome.Capability{
+					smarthome.NewCapability(smarthome.PowerController, "powerState"),
+					smarthome.NewCapability(smarthome.BrightnessController, "brightness"),
+				},
+			}), nil
+		}},
+		&smarthome.Route{Namespace: smarthome.PowerController, Handler: func(input smarthome.HandlerInput) (*smarthome.ResponseEnvelope, error) {
+			if input.GetDirective().Endpoint.EndpointID != "lamp" {
+				return nil, &smarthome.Error{Type: smarthome.ErrorNoSuchEndpoint, Message: "unknown endpoint"}
+			}
+			return smarthome.NewResponse(input).WithPowerState(input.GetDirective().Header.Name == "TurnOn"), nil
+		}},
+		&smarthome.Route{Namespace: smarthome.BrightnessController, Name: "SetBrightness", Handler: func(input smarthome.HandlerInput) (*smarthome.ResponseEnvelope, error) {
+			payload := smarthome.SetBrightnessPayload{}
+			if err := input.DecodePayload(&payload); err != nil {
+				return nil, err
+			}
+			if payload.Brightness > 100 {
+				return nil, smarthome.NewValueOutOfRangeError("brightness", 0, 100)
+			}
+			return smarthome.NewResponse(input).WithBrightness(payload.Brightness), nil
+		}},
+		&smarthome.Route{Namespace: smarthome.BrightnessController, Name: "AdjustBrightness", Handler: func(input smarthome.HandlerInput) (*smarthome.ResponseEnvelope, error) {
+			payload := smarthome.AdjustBrightnessPayload{}
+			if err := input.DecodePayload(&payload); err != nil {
+				return nil, err
+			}
+			// The lamp is at 50%
+			brightness := 50 + payload.BrightnessDelta
+			if brightness < 0 {
+				brightness = 0
+			} else if brightness > 100 {
+				brightness = 100
+			}
+			return smarthome.NewResponse(input).WithBrightness(brightness), nil
+		}},
+		&smarthome.Route{Namespace: smarthome.Alexa, Name: "ReportState", Handler: func(input smarthome.HandlerInput) (*smarthome.ResponseEnvelope, error) {
+			return nil, errors.New("device cloud is down")
+		}},
+	}}
+}
+
+func thermostatRouter() *smarthome.Router {
+	setpoint := smarthome.Temperature{Value: 20, Scale: "CELSIUS"}
+	mode := "HEAT"
+
+	return &smarthome.Router{Handlers: []smarthome.RequestHandler{
+		&smarthome.Route{Namespace: smarthome.ThermostatController, Name: "SetTargetTemperature", Handler: func(input smarthome.HandlerInput) (*smarthome.ResponseEnvelope, error) {
+			payload := smarthome.SetTargetTemperaturePayload{}
+			if err := input.DecodePayload(&payload); err != nil {
+				return nil, err
+			}
+			if payload.TargetSetpoint == nil {
+				return nil, &smarthome.Error{Type: smarthome.ErrorInvalidValue, Message: "only a single setpoint is supported"}
+			}
+			if payload.TargetSetpoint.Value < 10 || payload.TargetSetpoint.Value > 30 {
+				return nil, smarthome.NewValueOutOfRangeError("target setpoint", 10, 30)
+			}
+			setpoint = *payload.TargetSetpoint
+			return smarthome.NewResponse(input).WithTargetSetpoint(setpoint).WithThermostatMode(mode), nil
+		}},
+		&smarthome.Route{Namespace: smarthome.ThermostatController, Name: "SetThermostatMode", Handler: func(input smarthome.HandlerInput) (*smarthome.ResponseEnvelope, error) {
+			payload := smarthome.SetThermostatModePayload{}
+			if err := input.DecodePayload(&payload); err != nil {
+				return nil, err
+			}
+			mode = payload.ThermostatMode.Value
+			return smarthome.NewResponse(input).WithThermostatMode(mode), nil
+		}},
+		&smarthome.Route{Namespace: smarthome.Alexa, Name: "ReportState", Handler: func(input smarthome.HandlerInput) (*smarthome.ResponseEnvelope, error) {
+			return smarthome.NewStateReport(input).
+				WithTargetSetpoint(setpoint).
+				WithThermostatMode(mode).
+				WithTemperature(smarthome.Temperature{Value: 19.5, Scale: "CELSIUS"}).
+				WithConnectivity(true), nil
+		}},
+	}}
+}
+
+// properties returns the values of the context properties by namespace and name
+func properties(t *testing.T, result map[string]interface{}) map[string]interface{} {
+	state, ok := result["context"].(map[string]interface{})
+	require.True(t, ok, "the response has a context")
+	values := map[string]interface{}{}
+	for _, item := range state["properties"].([]interface{}) {
+		property := item.(map[string]interface{})
+		values[property["namespace"].(string)+"."+property["name"].(string)] = property["value"]
+	}
+	return values
+}
+
+func eventName(result map[string]interface{}) interface{} {
+	return result["event"].(map[string]interface{})["header"].(map[string]interface{})["name"]
+}
+
+func Test_Discovery(t *testing.T) {
+	result := invoke(t, lampRouter(), directive(smarthome.Discovery, "Discover", "", `{"scope": {"type": "BearerToken", "token": "access-token"}}`))
+
+	event := result["event"].(map[string]interface{})
+	require.Equal(t, "Discover.Response", event["header"].(map[string]interface{})["name"])
+	endpoints := event["payload"].(map[string]interface{})["endpoints"].([]interface{})
+	require.Len(t, endpoints, 1)
+	capabilities := endpoints[0].(map[string]interface{})["capabilities"].([]interface{})
+	require.Len(t, capabilities, 3)
+	require.Equal(t, smarthome.Alexa, capabilities[0].(map[string]interface{})["interface"])
+	require.Nil(t, result["context"])
+}
+
+func Test_PowerController(t *testing.T) {
+	result := invoke(t, lampRouter(), directive(smarthome.PowerController, "TurnOn", "lamp", `{}`))
+
+	header := result["event"].(map[string]interface{})["header"].(map[string]interface{})
+	require.Equal(t, "Response", header["name"])
+	require.Equal(t, "correlation-1", header["correlationToken"])
+	properties := result["context"].(map[string]interface{})["properties"].([]interface{})
+	require.Equal(t, "powerState", properties[0].(map[string]interface{})["name"])
+	require.Equal(t, "ON", properties[0].(map[string]interface{})["value"])
+}
+
+func Test_AdjustBrightness(t *testing.T) {
+	result := invoke(t, lampRouter(), directive(smarthome.BrightnessController, "AdjustBrightness", "lamp", `{"brightnessDelta": -20}`))
+	require.Equal(t, "Response", eventName(result))
+	require.Equal(t, 30.0, properties(t, result)["Alexa.BrightnessController.brightness"])
+
+	result = invoke(t, lampRouter(), directive(smarthome.BrightnessController, "AdjustBrightness", "lamp", `{"brightnessDelta": 80}`))
+	require.Equal(t, 100.0, properties(t, result)["Alexa.BrightnessController.brightness"])
+}
+
+func Test_ThermostatController(t *testing.T) {
+	router := thermostatRouter()
+
+	result := invoke(t, router, directive(smarthome.ThermostatController, "SetTargetTemperature", "thermostat",
+		`{"targetSetpoint": {"value": 22.5, "scale": "CELSIUS"}}`))
+	require.Equal(t, "Response", eventName(result))
+	values := properties(t, result)
+	require.Equal(t, map[string]interface{}{"value": 22.5, "scale": "CELSIUS"}, values["Alexa.ThermostatController.targetSetpoint"])
+	require.Equal(t, "HEAT", values["Alexa.ThermostatController.thermostatMode"])
+
+	result = invoke(t, router, directive(smarthome.ThermostatController, "SetThermostatMode", "thermostat",
+		`{"thermostatMode": {"value": "ECO"}}`))
+	require.Equal(t, "Response", eventName(result))
+	require.Equal(t, "ECO", properties(t, result)["Alexa.ThermostatController.thermostatMode"])
+
+	result = invoke(t, router, directive(smarthome.ThermostatController, "SetTargetTemperature", "thermostat",
+		`{"targetSetpoint": {"value": 35, "scale": "CELSIUS"}}`))
+	require.Equal(t, "ErrorResponse", eventName(result))
+	payload := result["event"].(map[string]interface{})["payload"].(map[string]interface{})
+	require.Equal(t, smarthome.ErrorValueOutOfRange, payload["type"])
+}
+
+func Test_ReportState(t *testing.T) {
+	router := thermostatRouter()
+	invoke(t, router, directive(smarthome.ThermostatController, "SetThermostatMode", "thermostat", `{"thermostatMode": {"value": "COOL"}}`))
+
+	result := invoke(t, router, directive(smarthome.Alexa, "ReportState", "thermostat", `{}`))
+	header := result["event"].(map[string]interface{})["header"].(map[string]interface{})
+	require.Equal(t, "StateReport", header["name"])
+	require.Equal(t, smarthome.Alexa, header["namespace"])
+	require.Equal(t, "correlation-1", header["correlationToken"])
+	endpoint := result["event"].(map[string]interface{})["endpoint"].(map[string]interface{})
+	require.Equal(t, "thermostat", endpoint["endpointId"])
+
+	require.Equal(t, map[string]interface{}{
+		"Alexa.ThermostatController.targetSetpoint": map[string]interface{}{"value": 20.0, "scale": "CELSIUS"},
+		"Alexa.ThermostatController.thermostatMode": "COOL",
+		"Alexa.TemperatureSensor.temperature":       map[string]interface{}{"value": 19.5, "scale": "CELSIUS"},
+		"Alexa.EndpointHealth.connectivity":         map[string]interface{}{"value": "OK"},
+	}, properties(t, result))
+}
+
+func Test_ErrorResponses(t *testing.T) {
+	errorPayload := func(result map[string]interface{}) map[string]interface{} {
+		event := result["event"].(map[string]interface{})
+		require.Equal(t, "ErrorResponse", event["header"].(map[string]interface{})["name"])
+		return event["payload"].(map[string]interface{})
+	}
+
+	payload := errorPayload(invoke(t, lampRouter(), directive(smarthome.PowerController, "TurnOff", "fan", `{}`)))
+	require.Equal(t, smarthome.ErrorNoSuchEndpoint, payload["type"])
+
+	payload = errorPayload(invoke(t, lampRouter(), directive(smarthome.BrightnessController, "SetBrightness", "lamp", `{"brightness": 150}`)))
+	require.Equal(t, smarthome.ErrorValueOutOfRange, payload["type"])
+	require.NotNil(t, payload["validRange"])
+
+	payload = errorPayload(invoke(t, lampRouter(), directive(smarthome.Alexa, "ReportState", "lamp", `{}`)))
+	require.Equal(t, smarthome.ErrorInternal, payload["type"])
+
+	payload = errorPayload(invoke(t, lampRouter(), directive(smarthome.ThermostatController, "SetTargetTemperature", "lamp", `{}`)))
+	require.Equal(t, smarthome.ErrorInvalidDirective, payload["type"])
+}
+
+func Test_ChangeReport(t *testing.T) {
+	type received struct {
+		path          string
+		authorization string
+		body          []byte
+	}
+	requests := make(chan received, 1)
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{path: r.URL.Path, authorization: r.Header.Get("Authorization"), body: body}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer gateway.Close()
+
+	report := smarthome.NewChangeReport("lamp", "grant-token", smarthome.CausePhysicalInteraction,
+		smarthome.NewProperty(smarthome.PowerController, "powerState", "ON")).WithBrightness(40)
+	client := &smarthome.Gateway{Endpoint: gateway.URL}
+	require.NoError(t, client.Send(context.Background(), "grant-token", report))
+
+	request := <-requests
+	require.Equal(t, smarthome.EventsPath, request.path)
+	require.Equal(t, "Bearer grant-token", request.authorization)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(request.body, &body))
+
+	event := body["event"].(map[string]interface{})
+	require.Equal(t, "ChangeReport", event["header"].(map[string]interface{})["name"])
+	change := event["payload"].(map[string]interface{})["change"].(map[string]interface{})
+	require.Equal(t, smarthome.CausePhysicalInteraction, change["cause"].(map[string]interface{})["type"])
+	require.Len(t, change["properties"].([]interface{}), 1)
+	require.Len(t, body["context"].(map[string]interface{})["properties"].([]interface{}), 1)
+}
+
+func Test_Panics(t *testing.T) {
+	router := &smarthome.Router{Handlers: []smarthome.RequestHandler{
+		&smarthome.Route{Namespace: smarthome.PowerController, Handler: func(input smarthome.HandlerInput) (*smarthome.ResponseEnvelope, error) {
+			var endpoints map[string]bool
+			endpoints["lamp"] = true
+			return smarthome.NewResponse(input), nil
+		}},
+	}}
+
+	envelope := &smarthome.DirectiveEnvelope{}
+	require.NoError(t, json.Unmarshal(directive(smarthome.PowerController, "TurnOn", "lamp", `{}`), envelope))
+	// A nil context is replaced by context.Background()
+	response, err := router.ProcessDirective(smarthome.NewDefaultHandler(nil, envelope))
+	require.NoError(t, err)
+	require.Equal(t, "ErrorResponse", response.Event.Header.Name)
+	require.Equal(t, smarthome.ErrorInternal, response.Event.Payload.(map[string]interface{})["type"])
+
+	var recovered error
+	router.ErrorHandlers = []smarthome.ErrorHandler{errorRecorder{&recovered}}
+	_, err = router.Invoke(context.Background(), directive(smarthome.PowerController, "TurnOn", "lamp", `{}`))
+	require.NoError(t, err)
+	var panicErr *askgo.PanicError
+	require.True(t, errors.As(recovered, &panicErr))
+	var handlerErr *askgo.HandlerError
+	require.True(t, errors.As(recovered, &handlerErr))
+	require.Equal(t, "Alexa.PowerController", handlerErr.Handler)
+}
+
+func Test_NoHandler(t *testing.T) {
+	var recovered error
+	router := lampRouter()
+	router.ErrorHandlers = []smarthome.ErrorHandler{errorRecorder{&recovered}}
+	invoke(t, router, directive(smarthome.ThermostatController, "SetThermostatMode", "lamp", `{}`))
+	require.True(t, errors.Is(recovered, askgo.ErrNoHandler))
+
+	router.FallbackHandler = func(input smarthome.HandlerInput) (*smarthome.ResponseEnvelope, error) {
+		return nil, &smarthome.Error{Type: smarthome.ErrorNotSupportedInCurrentMode, Message: "the lamp has no thermostat"}
+	}
+	router.ErrorHandlers = nil
+	result := invoke(t, router, directive(smarthome.ThermostatController, "SetThermostatMode", "lamp", `{}`))
+	payload := result["event"].(map[string]interface{})["payload"].(map[string]interface{})
+	require.Equal(t, smarthome.ErrorNotSupportedInCurrentMode, payload["type"])
+}
+
+// errorRecorder keeps the error it handles and answers with an internal error
+type errorRecorder struct {
+	err *error
+}
+
+func (recorder errorRecorder) CanHandle(input smarthome.HandlerInput, err error) bool { return true }
+
+func (recorder errorRecorder) Handle(input smarthome.HandlerInput, err error) (*smarthome.ResponseEnvelope, error) {
+	*recorder.err = err
+	return smarthome.NewErrorResponse(input, &smarthome.Error{Type: smarthome.ErrorInternal}), nil
+}
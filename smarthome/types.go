@@ -0,0 +1,142 @@
+// Package smarthome handles the directives of the Smart Home Skill API (version 3) with the same
+// handler and interceptor model as custom skills.
+//
+//	router := &smarthome.Router{Handlers: []smarthome.RequestHandler{
+//		&smarthome.Route{Namespace: smarthome.Discovery, Name: "Discover", Handler: discover},
+//		&smarthome.Route{Namespace: smarthome.PowerController, Handler: power},
+//		&smarthome.Route{Namespace: smarthome.Alexa, Name: "ReportState", Handler: reportState},
+//	}}
+//	lambda.StartHandler(router)
+package smarthome
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Interface namespaces
+const (
+	Alexa                = "Alexa"
+	Discovery            = "Alexa.Discovery"
+	Authorization        = "Alexa.Authorization"
+	PowerController      = "Alexa.PowerController"
+	BrightnessController = "Alexa.BrightnessController"
+	ThermostatController = "Alexa.ThermostatController"
+	TemperatureSensor    = "Alexa.TemperatureSensor"
+	EndpointHealth       = "Alexa.EndpointHealth"
+)
+
+// PayloadVersion is the version of the Smart Home Skill API
+const PayloadVersion = "3"
+
+// DirectiveEnvelope is the request sent by Alexa
+type DirectiveEnvelope struct {
+	Directive Directive `json:"directive"`
+}
+
+// Directive is a command or query for an endpoint, the payload depends on the namespace and name
+type Directive struct {
+	Header   Header          `json:"header"`
+	Endpoint *Endpoint       `json:"endpoint,omitempty"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// Header identifies a directive or event
+type Header struct {
+	Namespace        string `json:"namespace"`
+	Name             string `json:"name"`
+	Instance         string `json:"instance,omitempty"`
+	PayloadVersion   string `json:"payloadVersion"`
+	MessageID        string `json:"messageId"`
+	CorrelationToken string `json:"correlationToken,omitempty"`
+}
+
+// Endpoint is a device, or a group of devices, of the user
+type Endpoint struct {
+	Scope      *Scope            `json:"scope,omitempty"`
+	EndpointID string            `json:"endpointId"`
+	Cookie     map[string]string `json:"cookie,omitempty"`
+}
+
+// Scope carries the account linking access token of the user
+type Scope struct {
+	Type  string `json:"type"`
+	Token string `json:"token"`
+}
+
+// ResponseEnvelope is the response to a directive, or an event sent to the event gateway
+type ResponseEnvelope struct {
+	Event   Event    `json:"event"`
+	Context *Context `json:"context,omitempty"`
+}
+
+// Event is the body of a response or event
+type Event struct {
+	Header   Header      `json:"header"`
+	Endpoint *Endpoint   `json:"endpoint,omitempty"`
+	Payload  interface{} `json:"payload"`
+}
+
+// Context holds the state of the endpoint
+type Context struct {
+	Properties []Property `json:"properties"`
+}
+
+// Property is a value of the state of an endpoint (e.g. Alexa.PowerController powerState "ON")
+type Property struct {
+	Namespace                 string      `json:"namespace"`
+	Instance                  string      `json:"instance,omitempty"`
+	Name                      string      `json:"name"`
+	Value                     interface{} `json:"value"`
+	TimeOfSample              time.Time   `json:"timeOfSample"`
+	UncertaintyInMilliseconds int         `json:"uncertaintyInMilliseconds"`
+}
+
+// NewProperty returns a property sampled now
+func NewProperty(namespace, name string, value interface{}) Property {
+	return Property{Namespace: namespace, Name: name, Value: value, TimeOfSample: time.Now().UTC()}
+}
+
+// Temperature is a temperature value, Scale is "CELSIUS", "FAHRENHEIT" or "KELVIN"
+type Temperature struct {
+	Value float64 `json:"value"`
+	Scale string  `json:"scale"`
+}
+
+// DiscoverPayload is the payload of Alexa.Discovery Discover
+type DiscoverPayload struct {
+	Scope Scope `json:"scope"`
+}
+
+// SetBrightnessPayload is the payload of Alexa.BrightnessController SetBrightness
+type SetBrightnessPayload struct {
+	Brightness int `json:"brightness"`
+}
+
+// AdjustBrightnessPayload is the payload of Alexa.BrightnessController AdjustBrightness
+type AdjustBrightnessPayload struct {
+	BrightnessDelta int `json:"brightnessDelta"`
+}
+
+// SetTargetTemperaturePayload is the payload of Alexa.ThermostatController SetTargetTemperature,
+// either TargetSetpoint or the lower and upper setpoints are given
+type SetTargetTemperaturePayload struct {
+	TargetSetpoint *Temperature `json:"targetSetpoint,omitempty"`
+	LowerSetpoint  *Temperature `json:"lowerSetpoint,omitempty"`
+	UpperSetpoint  *Temperature `json:"upperSetpoint,omitempty"`
+}
+
+// AdjustTargetTemperaturePayload is the payload of Alexa.ThermostatController AdjustTargetTemperature
+type AdjustTargetTemperaturePayload struct {
+	TargetSetpointDelta Temperature `json:"targetSetpointDelta"`
+}
+
+// ThermostatMode is a mode of a thermostat ("HEAT", "COOL", "AUTO", "ECO", "OFF")
+type ThermostatMode struct {
+	Value string `json:"value"`
+}
+
+// SetThermostatModePayload is the payload of Alexa.ThermostatController SetThermostatMode
+type SetThermostatModePayload struct {
+	ThermostatMode ThermostatMode `json:"thermostatMode"`
+}
@@ -0,0 +1,53 @@
+package standard
+
+import "github.com/koblas/askgo/i18n"
+
+// DefaultMessages are the built-in messages of the standard handlers, by language
+var DefaultMessages = newDefaultMessages()
+
+func newDefaultMessages() *i18n.Bundle {
+	bundle := i18n.NewBundle("en")
+
+	add := func(locale, help, helpReprompt, goodbye, fallback, fallbackReprompt string) {
+		bundle.AddCatalog(locale, i18n.Catalog{
+			HelpMessage:      {Variants: []string{help}},
+			HelpReprompt:     {Variants: []string{helpReprompt}},
+			GoodbyeMessage:   {Variants: []string{goodbye}},
+			FallbackMessage:  {Variants: []string{fallback}},
+			FallbackReprompt: {Variants: []string{fallbackReprompt}},
+		})
+	}
+
+	add("en",
+		"You can ask me to get started, or say stop to leave. What would you like to do?",
+		"What would you like to do?",
+		"Goodbye!",
+		"Sorry, I can't help with that. You can say help to hear what I can do.",
+		"What would you like to do?")
+	add("de",
+		"Du kannst mich bitten, anzufangen, oder stopp sagen, um aufzuhören. Was möchtest du tun?",
+		"Was möchtest du tun?",
+		"Auf Wiedersehen!",
+		"Damit kann ich leider nicht helfen. Sag Hilfe, um zu hören, was ich kann.",
+		"Was möchtest du tun?")
+	add("es",
+		"Puedes pedirme que empiece, o decir para para salir. ¿Qué quieres hacer?",
+		"¿Qué quieres hacer?",
+		"¡Adiós!",
+		"Lo siento, no puedo ayudarte con eso. Puedes decir ayuda para saber lo que puedo hacer.",
+		"¿Qué quieres hacer?")
+	add("fr",
+		"Tu peux me demander de commencer, ou dire stop pour quitter. Que veux-tu faire ?",
+		"Que veux-tu faire ?",
+		"Au revoir !",
+		"Désolé, je ne peux pas t'aider avec ça. Dis aide pour savoir ce que je peux faire.",
+		"Que veux-tu faire ?")
+	add("it",
+		"Puoi chiedermi di iniziare, oppure dire stop per uscire. Cosa vuoi fare?",
+		"Cosa vuoi fare?",
+		"Arrivederci!",
+		"Mi dispiace, non posso aiutarti con questo. Puoi dire aiuto per sapere cosa posso fare.",
+		"Cosa vuoi fare?")
+
+	return bundle
+}
@@ -0,0 +1,119 @@
+// Package standard provides the request handlers every skill needs: help, stop/cancel/pause,
+// AMAZON.FallbackIntent, AMAZON.NavigateHomeIntent, SessionEndedRequest and
+// System.ExceptionEncountered.
+//
+//	skill.Handlers = append(skill.Handlers, standard.Default()...)
+//
+// They are meant to be added after the handlers of the skill, so a skill can still handle
+// some of these requests itself.
+package standard
+
+import (
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/koblas/askgo/i18n"
+)
+
+// Keys of the messages spoken by the standard handlers
+const (
+	HelpMessage      = "STANDARD_HELP"
+	HelpReprompt     = "STANDARD_HELP_REPROMPT"
+	GoodbyeMessage   = "STANDARD_GOODBYE"
+	FallbackMessage  = "STANDARD_FALLBACK"
+	FallbackReprompt = "STANDARD_FALLBACK_REPROMPT"
+)
+
+// Handlers configures the standard handlers.  Messages are looked up in Messages, then in
+// the catalogs of the skill (Skill.Messages) and finally in DefaultMessages, so any of them
+// can be replaced per locale.
+type Handlers struct {
+	// Messages overrides the messages of the standard handlers
+	Messages *i18n.Bundle
+
+	// Help handles AMAZON.HelpIntent, defaults to speaking the help message
+	Help askgo.HandlerFunc
+	// Exit handles AMAZON.StopIntent, AMAZON.CancelIntent and AMAZON.PauseIntent, defaults
+	// to saying goodbye and ending the session
+	Exit askgo.HandlerFunc
+	// NavigateHome handles AMAZON.NavigateHomeIntent, defaults to Exit
+	NavigateHome askgo.HandlerFunc
+	// Fallback handles AMAZON.FallbackIntent, defaults to speaking the fallback message
+	Fallback askgo.HandlerFunc
+	// SessionEnded handles SessionEndedRequest, defaults to logging the reason
+	SessionEnded askgo.HandlerFunc
+	// ExceptionEncountered handles System.ExceptionEncountered, defaults to logging the error
+	ExceptionEncountered askgo.HandlerFunc
+}
+
+// Default returns the standard handlers with the default messages
+func Default() []askgo.RequestHandler {
+	return (&Handlers{}).RequestHandlers()
+}
+
+// RequestHandlers returns the standard handlers to add to Skill.Handlers
+func (handlers *Handlers) RequestHandlers() []askgo.RequestHandler {
+	exit := orDefault(handlers.Exit, handlers.exit)
+
+	return []askgo.RequestHandler{
+		&askgo.Route{Name: "standard.help", Intents: []string{alexa.HelpIntent}, Handler: orDefault(handlers.Help, handlers.help)},
+		&askgo.Route{Name: "standard.exit", Intents: []string{alexa.StopIntent, alexa.CancelIntent, alexa.PauseIntent}, Handler: exit},
+		&askgo.Route{Name: "standard.navigate_home", Intents: []string{alexa.NavigateHomeIntent}, Handler: orDefault(handlers.NavigateHome, exit)},
+		&askgo.Route{Name: "standard.fallback", Intents: []string{alexa.FallbackIntent}, Handler: orDefault(handlers.Fallback, handlers.fallback)},
+		&askgo.Route{Name: "standard.session_ended", RequestType: "SessionEndedRequest", Handler: orDefault(handlers.SessionEnded, sessionEnded)},
+		&askgo.Route{Name: "standard.exception", RequestType: "System.ExceptionEncountered", Handler: orDefault(handlers.ExceptionEncountered, exceptionEncountered)},
+	}
+}
+
+func orDefault(handler, fallback askgo.HandlerFunc) askgo.HandlerFunc {
+	if handler != nil {
+		return handler
+	}
+	return fallback
+}
+
+// Message returns the message for the key in the locale of the request
+func (handlers *Handlers) Message(input askgo.HandlerInput, key string) string {
+	locale := input.GetRequest().Locale
+	if handlers.Messages != nil {
+		if localizer := handlers.Messages.Localizer(locale); localizer.Has(key) {
+			return localizer.T(key)
+		}
+	}
+	if text := input.T(key); text != key {
+		return text
+	}
+	return DefaultMessages.Localizer(locale).T(key)
+}
+
+func (handlers *Handlers) help(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+	return input.GetResponse().Speak(handlers.Message(input, HelpMessage)).
+		Reprompt(handlers.Message(input, HelpReprompt)), nil
+}
+
+func (handlers *Handlers) exit(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+	return input.GetResponse().Speak(handlers.Message(input, GoodbyeMessage)).WithShouldEndSession(true), nil
+}
+
+func (handlers *Handlers) fallback(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+	return input.GetResponse().Speak(handlers.Message(input, FallbackMessage)).
+		Reprompt(handlers.Message(input, FallbackReprompt)), nil
+}
+
+func sessionEnded(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+	request := input.GetRequest()
+	if request.Reason == "ERROR" {
+		input.GetLogger().Warn("Session ended with an error", "reason", request.Reason,
+			"error_type", request.Error.Type, "error_message", request.Error.Message)
+	} else {
+		input.GetLogger().Info("Session ended", "reason", request.Reason)
+	}
+	// Alexa does not accept speech in response to a SessionEndedRequest
+	return input.GetResponse(), nil
+}
+
+func exceptionEncountered(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+	request := input.GetRequest()
+	input.GetLogger().Error("Alexa encountered an error with a response of the skill",
+		"error_type", request.Error.Type, "error_message", request.Error.Message, "cause_request_id", request.Cause.RequestID)
+	return input.GetResponse(), nil
+}
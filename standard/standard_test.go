@@ -0,0 +1,59 @@
+package standard_test
+
+import (
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/koblas/askgo/askgotest"
+	"github.com/koblas/askgo/i18n"
+	"github.com/koblas/askgo/standard"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Default(t *testing.T) {
+	skill := &askgo.Skill{IgnoreTimestamp: true, Handlers: standard.Default()}
+	conv := askgotest.NewConversation(skill)
+
+	conv.Run(t, askgotest.Script{
+		{Intent: alexa.HelpIntent, Expect: askgotest.Expect{SpeechContains: "say stop", Reprompt: "What would you like to do?"}},
+		{Intent: alexa.FallbackIntent, Expect: askgotest.Expect{SpeechContains: "can't help"}},
+		{Intent: alexa.NavigateHomeIntent, Expect: askgotest.Expect{Speech: "Goodbye!", ShouldEndSession: askgotest.Bool(true)}},
+		{Intent: alexa.StopIntent, Expect: askgotest.Expect{Speech: "Goodbye!", ShouldEndSession: askgotest.Bool(true)}},
+	})
+
+	response, err := conv.End("USER_INITIATED")
+	require.NoError(t, err)
+	require.Empty(t, askgotest.Speech(response))
+
+	conv.Locale = "de-DE"
+	response, err = conv.Intent(alexa.CancelIntent, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Auf Wiedersehen!", askgotest.Speech(response))
+}
+
+func Test_Messages(t *testing.T) {
+	catalogs := i18n.NewBundle("en")
+	catalogs.AddCatalog("en", i18n.Catalog{standard.GoodbyeMessage: {Variants: []string{"See you soon"}}})
+	overrides := i18n.NewBundle("")
+	overrides.AddCatalog("en-GB", i18n.Catalog{standard.GoodbyeMessage: {Variants: []string{"Cheerio"}}})
+
+	handlers := &standard.Handlers{
+		Messages: overrides,
+		Help: func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+			return input.GetResponse().Speak("Custom help"), nil
+		},
+	}
+	skill := &askgo.Skill{IgnoreTimestamp: true, Messages: catalogs, Handlers: handlers.RequestHandlers()}
+	conv := askgotest.NewConversation(skill)
+
+	conv.Run(t, askgotest.Script{
+		{Intent: alexa.HelpIntent, Expect: askgotest.Expect{Speech: "Custom help"}},
+		{Intent: alexa.StopIntent, Expect: askgotest.Expect{Speech: "See you soon"}},
+	})
+
+	conv.Locale = "en-GB"
+	response, err := conv.Intent(alexa.StopIntent, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Cheerio", askgotest.Speech(response))
+}
@@ -0,0 +1,212 @@
+package askgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/koblas/askgo/alexa"
+)
+
+// StateAttribute is the session attribute holding the current state of a StateMachine
+const StateAttribute = "STATE"
+
+// State is a state of the conversation and the requests that are valid in it
+type State struct {
+	// Name of the state stored in the session attributes (e.g. "QUIZ")
+	Name string
+	// Handlers of the requests valid in this state, the first that can handle a request is used
+	Handlers []RequestHandler
+	// Transitions gives the state to move to after an intent was handled, by intent name,
+	// unless the handler called SetState
+	Transitions map[string]string
+	// Help handles AMAZON.HelpIntent when no handler of the state does
+	Help HandlerFunc
+	// Unhandled handles the other intent requests no handler of the state can handle
+	Unhandled HandlerFunc
+}
+
+// StateMachine is a RequestHandler routing requests by the state of the conversation and
+// the intent, the current state is kept in the session attributes of the responses.
+//
+//	skill.Handlers = append(skill.Handlers, &askgo.StateMachine{
+//		Initial: "START",
+//		States: []*askgo.State{
+//			{Name: "START", Handlers: []askgo.RequestHandler{askgo.IntentRoute(startQuiz, "QuizIntent")},
+//				Transitions: map[string]string{"QuizIntent": "QUIZ"}, Unhandled: startHelp},
+//			{Name: "QUIZ", Handlers: []askgo.RequestHandler{askgo.IntentRoute(answer, "AnswerIntent")},
+//				Help: quizHelp, Unhandled: quizUnhandled},
+//		},
+//	})
+type StateMachine struct {
+	// Initial is the state of a session without a state attribute
+	Initial string
+	// States of the conversation
+	States []*State
+	// Attribute is the session attribute holding the state, defaults to StateAttribute
+	Attribute string
+	// Name of the state machine in metrics, defaults to "StateMachine"
+	Name string
+}
+
+var _ RequestHandler = &StateMachine{}
+var _ IntentLister = &StateMachine{}
+var _ HandlerNamer = &StateMachine{}
+
+func (machine *StateMachine) attribute() string {
+	if machine.Attribute != "" {
+		return machine.Attribute
+	}
+	return StateAttribute
+}
+
+// CurrentState returns the name of the state of the session
+func (machine *StateMachine) CurrentState(input HandlerInput) string {
+	if name, ok := input.GetRequestEnvelope().Session.Attributes[machine.attribute()].(string); ok && name != "" {
+		return name
+	}
+	return machine.Initial
+}
+
+// FindState returns the state with the name, or nil
+func (machine *StateMachine) FindState(name string) *State {
+	for _, state := range machine.States {
+		if state.Name == name {
+			return state
+		}
+	}
+	return nil
+}
+
+// route returns the handler of the request in the current state, or nil
+func (machine *StateMachine) route(input HandlerInput) (*State, RequestHandler) {
+	state := machine.FindState(machine.CurrentState(input))
+	if state == nil {
+		return nil, nil
+	}
+
+	for _, handler := range state.Handlers {
+		if handler.CanHandle(input) {
+			return state, handler
+		}
+	}
+
+	request := input.GetRequest()
+	if request.Type != "IntentRequest" {
+		return state, nil
+	}
+	if state.Help != nil && request.Intent.Name == alexa.HelpIntent {
+		return state, &Route{Name: state.Name + ".help", Handler: state.Help}
+	}
+	if state.Unhandled != nil {
+		return state, &Route{Name: state.Name + ".unhandled", Handler: state.Unhandled}
+	}
+	return state, nil
+}
+
+// CanHandle accepts the requests that have a handler in the current state
+func (machine *StateMachine) CanHandle(input HandlerInput) bool {
+	_, handler := machine.route(input)
+	return handler != nil
+}
+
+// Handle calls the handler of the current state and stores the next state in the session
+// attributes of the response, where it is set again after the response interceptors in case
+// they replaced the session attributes
+func (machine *StateMachine) Handle(input HandlerInput) (*ResponseEnvelope, error) {
+	state, handler := machine.route(input)
+	if handler == nil {
+		return nil, fmt.Errorf("askgo: state %q has no handler for the request", machine.CurrentState(input))
+	}
+
+	transition := &stateTransition{}
+	input.SetContext(context.WithValue(input.GetContext(), stateKey{}, transition))
+
+	response, err := handler.Handle(input)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil {
+		response = input.GetResponse()
+	}
+
+	next := state.Name
+	if transition.set {
+		next = transition.state
+	} else if to, found := state.Transitions[input.GetRequest().Intent.Name]; found {
+		next = to
+	}
+	if states, ok := input.GetContext().Value(nextStatesKey{}).(nextStates); ok {
+		states[machine.attribute()] = next
+	}
+	return response.WithSessionAttribute(machine.attribute(), next), nil
+}
+
+// HandledIntents returns the intents of the handlers and transitions of every state
+func (machine *StateMachine) HandledIntents() []string {
+	seen := make(map[string]bool)
+	for _, state := range machine.States {
+		for _, handler := range state.Handlers {
+			if lister, ok := handler.(IntentLister); ok {
+				for _, name := range lister.HandledIntents() {
+					seen[name] = true
+				}
+			}
+		}
+		for name := range state.Transitions {
+			seen[name] = true
+		}
+	}
+
+	intents := make([]string, 0, len(seen))
+	for name := range seen {
+		intents = append(intents, name)
+	}
+	sort.Strings(intents)
+	return intents
+}
+
+// HandlerName returns the name of the state machine
+func (machine *StateMachine) HandlerName() string {
+	if machine.Name != "" {
+		return machine.Name
+	}
+	return "StateMachine"
+}
+
+type stateKey struct{}
+
+type nextStatesKey struct{}
+
+// nextStates are the states the state machines of a request moved to, by session attribute
+type nextStates map[string]string
+
+func withNextStates(ctx context.Context) (context.Context, nextStates) {
+	states := make(nextStates)
+	return context.WithValue(ctx, nextStatesKey{}, states), states
+}
+
+// apply stores the states in the session attributes of the response
+func (states nextStates) apply(response *ResponseEnvelope) {
+	if response == nil {
+		return
+	}
+	for attribute, state := range states {
+		response.WithSessionAttribute(attribute, state)
+	}
+}
+
+// stateTransition is the state chosen by a handler of a StateMachine
+type stateTransition struct {
+	set   bool
+	state string
+}
+
+// SetState moves the conversation to the state once the current handler of a StateMachine
+// returns, overriding the transitions of the state
+func SetState(input HandlerInput, name string) {
+	if transition, ok := input.GetContext().Value(stateKey{}).(*stateTransition); ok {
+		transition.set = true
+		transition.state = name
+	}
+}
@@ -0,0 +1,99 @@
+package askgo_test
+
+import (
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/koblas/askgo/askgotest"
+	"github.com/stretchr/testify/require"
+)
+
+func say(speech string) askgo.HandlerFunc {
+	return func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+		return input.GetResponse().Speak(speech), nil
+	}
+}
+
+func quizMachine() *askgo.StateMachine {
+	return &askgo.StateMachine{
+		Initial: "START",
+		States: []*askgo.State{
+			{
+				Name:        "START",
+				Handlers:    []askgo.RequestHandler{askgo.IntentRoute(say("First question"), "QuizIntent")},
+				Transitions: map[string]string{"QuizIntent": "QUIZ"},
+				Unhandled:   say("Say start a quiz"),
+			},
+			{
+				Name: "QUIZ",
+				Handlers: []askgo.RequestHandler{askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+					if input.GetRequest().Intent.Slots["Answer"].Value == "done" {
+						askgo.SetState(input, "START")
+						return input.GetResponse().Speak("Quiz over"), nil
+					}
+					return input.GetResponse().Speak("Next question"), nil
+				}, "AnswerIntent")},
+				Help:      say("Answer the question"),
+				Unhandled: say("Please answer the question"),
+			},
+		},
+	}
+}
+
+func Test_StateMachine(t *testing.T) {
+	machine := quizMachine()
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		Handlers:        []askgo.RequestHandler{machine, askgo.IntentRoute(say("Global help"), alexa.HelpIntent)},
+	}
+	conv := askgotest.NewConversation(skill)
+
+	conv.Run(t, askgotest.Script{
+		{Intent: alexa.HelpIntent, Expect: askgotest.Expect{Speech: "Say start a quiz"}},
+		{Intent: "AnswerIntent", Expect: askgotest.Expect{Speech: "Say start a quiz"}},
+		{Intent: "QuizIntent", Expect: askgotest.Expect{Speech: "First question"}},
+		{Intent: alexa.HelpIntent, Expect: askgotest.Expect{Speech: "Answer the question"}},
+		{Intent: "AnswerIntent", Slots: map[string]string{"Answer": "paris"}, Expect: askgotest.Expect{Speech: "Next question"}},
+		{Intent: "QuizIntent", Expect: askgotest.Expect{Speech: "Please answer the question"}},
+	})
+	require.Equal(t, "QUIZ", conv.SessionAttributes()[askgo.StateAttribute])
+
+	_, err := conv.Intent("AnswerIntent", map[string]string{"Answer": "done"})
+	require.NoError(t, err)
+	require.Equal(t, "START", conv.SessionAttributes()[askgo.StateAttribute])
+
+	require.Equal(t, []string{"AnswerIntent", "QuizIntent"}, machine.HandledIntents())
+}
+
+func Test_StateMachineUnknownState(t *testing.T) {
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		Handlers:        []askgo.RequestHandler{quizMachine(), askgo.IntentRoute(say("Global help"), alexa.HelpIntent)},
+	}
+	conv := askgotest.NewConversation(skill)
+	_, err := conv.Intent("QuizIntent", nil)
+	require.NoError(t, err)
+	conv.SetSessionAttributes(map[string]interface{}{askgo.StateAttribute: "GONE"})
+
+	// Requests in a state the machine does not know go to the other handlers
+	response, err := conv.Intent(alexa.HelpIntent, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Global help", askgotest.Speech(response))
+}
+
+func Test_StateMachineKeepsStateAfterInterceptors(t *testing.T) {
+	skill := &askgo.Skill{
+		IgnoreTimestamp:      true,
+		Handlers:             []askgo.RequestHandler{quizMachine()},
+		ResponseInterceptors: []askgo.ResponseInterceptor{&attributeSaver{}},
+	}
+	conv := askgotest.NewConversation(skill)
+
+	// The interceptor replaces the session attributes wholesale
+	conv.Run(t, askgotest.Script{
+		{Intent: "QuizIntent", Expect: askgotest.Expect{Speech: "First question"}},
+		{Intent: alexa.HelpIntent, Expect: askgotest.Expect{Speech: "Answer the question"}},
+	})
+	require.Equal(t, map[string]interface{}{"saved": true, askgo.StateAttribute: "QUIZ"}, conv.SessionAttributes())
+}
@@ -0,0 +1,173 @@
+package askgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/koblas/askgo/alexa"
+	"github.com/koblas/askgo/i18n"
+)
+
+// lambdaDeadlineMargin is kept from the Lambda deadline to return the timeout response
+const lambdaDeadlineMargin = 200 * time.Millisecond
+
+// hasDeadline reports whether the request must be processed with a deadline, either the
+// Timeout of the skill or the deadline of the Lambda invocation
+func (skill *Skill) hasDeadline(input HandlerInput) bool {
+	_, ok := input.GetContext().Deadline()
+	return ok || skill.Timeout > 0
+}
+
+// processWithTimeout processes the request with a deadline, on expiry the TimeoutError is
+// dispatched to the error handlers while the handler is left to finish in the background,
+// without the response interceptors, and its result is discarded
+func (skill *Skill) processWithTimeout(input HandlerInput, scope *requestScope) (interface{}, error) {
+	parent := input.GetContext()
+	start := time.Now()
+
+	var deadline time.Time
+	if skill.Timeout > 0 {
+		deadline = start.Add(skill.Timeout)
+	}
+	if lambdaDeadline, ok := parent.Deadline(); ok {
+		if lambdaDeadline = lambdaDeadline.Add(-lambdaDeadlineMargin); deadline.IsZero() || lambdaDeadline.Before(deadline) {
+			deadline = lambdaDeadline
+		}
+	}
+
+	// The error handlers get their own copy of the request, the handler may still be
+	// changing it when the deadline expires
+	envelope := copyEnvelope(input.GetRequestEnvelope())
+
+	ctx, cancel := context.WithDeadline(parent, deadline)
+	defer cancel()
+	input.SetContext(ctx)
+	scope.deadline = true
+
+	type result struct {
+		response interface{}
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		response, err := skill.process(input, scope)
+		done <- result{response, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.response, r.err
+	case <-ctx.Done():
+	}
+	if !scope.timeOut() {
+		// The handler finished and its response interceptors are running
+		r := <-done
+		return r.response, r.err
+	}
+
+	// The handler may still be using the input, the error handlers get their own
+	detached := &detachedInput{HandlerInput: input, envelope: envelope, ctx: context.WithoutCancel(ctx)}
+	err := &TimeoutError{Timeout: deadline.Sub(start), Err: ctx.Err()}
+	detached.GetLogger().Warn("Request timed out", "timeout", err.Timeout)
+
+	return skill.dispatchError(detached, scope, err)
+}
+
+// detachedInput is an input with its own copy of the request, context and response
+type detachedInput struct {
+	HandlerInput
+	envelope RequestEnvelope
+	ctx      context.Context
+	response *ResponseEnvelope
+}
+
+// copyEnvelope returns a deep copy of the envelope, made through JSON like the envelopes sent
+// by Alexa, or the envelope itself when it cannot be encoded
+func copyEnvelope(envelope RequestEnvelope) RequestEnvelope {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return envelope
+	}
+	var result RequestEnvelope
+	if err := json.Unmarshal(data, &result); err != nil {
+		return envelope
+	}
+	return result
+}
+
+func (input *detachedInput) GetRequestEnvelope() RequestEnvelope {
+	return input.envelope
+}
+
+func (input *detachedInput) GetRequest() Request {
+	return input.envelope.Request
+}
+
+func (input *detachedInput) GetContext() context.Context {
+	return input.ctx
+}
+
+func (input *detachedInput) SetContext(ctx context.Context) {
+	input.ctx = ctx
+}
+
+func (input *detachedInput) GetResponse() *ResponseEnvelope {
+	if input.response == nil {
+		input.response = &ResponseEnvelope{alexa.ResponseEnvelope{Version: "1.0"}}
+	}
+	return input.response
+}
+
+func (input *detachedInput) T(key string, args ...interface{}) string {
+	return i18n.FromContext(input.ctx).T(key, args...)
+}
+
+func (input *detachedInput) GetLogger() Logger {
+	return LoggerFromContext(input.ctx)
+}
+
+// TimeoutHandler returns an error handler answering the requests that timed out with the
+// speech, it must come before any error handler accepting all errors
+func TimeoutHandler(speech string) ErrorHandler {
+	return &timeoutHandler{speech}
+}
+
+type timeoutHandler struct {
+	speech string
+}
+
+func (handler *timeoutHandler) CanHandle(input HandlerInput, err error) bool {
+	var timeoutErr *TimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
+func (handler *timeoutHandler) Handle(input HandlerInput, err error) (*ResponseEnvelope, error) {
+	return input.GetResponse().Speak(handler.speech), nil
+}
+
+func (handler *timeoutHandler) HandlerName() string {
+	return "timeout"
+}
+
+// startProgressiveResponse sends the progressive speech when the request is still being
+// processed after the delay, the returned function cancels it
+func (skill *Skill) startProgressiveResponse(input HandlerInput) func() {
+	envelope := input.GetRequestEnvelope()
+	if envelope.Request.Type != "LaunchRequest" && envelope.Request.Type != "IntentRequest" ||
+		envelope.Context.System.APIAccessToken == "" {
+		return func() {}
+	}
+
+	ctx := input.GetContext()
+	logger := input.GetLogger()
+	client := NewServiceClient(input)
+
+	timer := time.AfterFunc(skill.ProgressiveDelay, func() {
+		if err := client.SendProgressiveResponse(ctx, envelope.Request.RequestID, skill.ProgressiveSpeech); err != nil {
+			logger.Warn("Unable to send the progressive response", "error", err)
+		}
+	})
+	return func() { timer.Stop() }
+}
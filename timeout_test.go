@@ -0,0 +1,173 @@
+package askgo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Timeout(t *testing.T) {
+	progressive := make(chan map[string]interface{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/directives", r.URL.Path)
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		json.Unmarshal(data, &body)
+		progressive <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	finished := make(chan error, 1)
+	skill := &askgo.Skill{
+		IgnoreTimestamp:   true,
+		Logger:            discardLogger,
+		Timeout:           200 * time.Millisecond,
+		ProgressiveSpeech: "Let me check",
+		ProgressiveDelay:  10 * time.Millisecond,
+		Handlers: []askgo.RequestHandler{
+			askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				<-input.GetContext().Done()
+				finished <- input.GetContext().Err()
+				return input.GetResponse().Speak("Too late"), nil
+			}, "SlowIntent"),
+			askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				return input.GetResponse().Speak("Done"), nil
+			}, "FastIntent"),
+		},
+		ErrorHandlers: []askgo.ErrorHandler{askgo.TimeoutHandler("Sorry, that's taking too long")},
+	}
+
+	envelope := func(intent string) *askgo.RequestEnvelope {
+		return &askgo.RequestEnvelope{
+			Request: alexa.Request{Type: "IntentRequest", RequestID: "request-1", Intent: alexa.Intent{Name: intent}},
+			Context: alexa.Context{System: alexa.System{APIEndpoint: server.URL, APIAccessToken: "token"}},
+		}
+	}
+
+	result, err := skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), envelope("SlowIntent")))
+	require.NoError(t, err)
+	require.Equal(t, "<speak>Sorry, that's taking too long</speak>", result.(*askgo.ResponseEnvelope).Response.OutputSpeech.SSML)
+	require.Equal(t, context.DeadlineExceeded, <-finished)
+
+	body := <-progressive
+	require.Equal(t, map[string]interface{}{"requestId": "request-1"}, body["header"])
+	require.Equal(t, map[string]interface{}{"type": "VoicePlayer.Speak", "speech": "Let me check"}, body["directive"])
+
+	result, err = skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), envelope("FastIntent")))
+	require.NoError(t, err)
+	require.Equal(t, "<speak>Done</speak>", result.(*askgo.ResponseEnvelope).Response.OutputSpeech.SSML)
+	require.Len(t, progressive, 0)
+
+	// A shorter Lambda deadline wins over the timeout
+	skill.Timeout = time.Minute
+	skill.ErrorHandlers = nil
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	_, err = skill.ProcessRequest(askgo.NewDefaultHandler(ctx, envelope("SlowIntent")))
+	var timeoutErr *askgo.TimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.True(t, timeoutErr.Timeout < time.Second)
+	<-finished
+}
+
+type attributeReporter struct{}
+
+func (attributeReporter) CanHandle(input askgo.HandlerInput, err error) bool { return true }
+
+func (attributeReporter) Handle(input askgo.HandlerInput, err error) (*askgo.ResponseEnvelope, error) {
+	return input.GetResponse().Speak(input.GetRequestEnvelope().Session.Attributes["step"].(string)), nil
+}
+
+func Test_LambdaDeadline(t *testing.T) {
+	finished := make(chan struct{})
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		Logger:          discardLogger,
+		Handlers: []askgo.RequestHandler{
+			askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				input.GetRequestEnvelope().Session.Attributes["step"] = "changed"
+				<-input.GetContext().Done()
+				for i := 0; i < 100; i++ {
+					input.GetRequestEnvelope().Session.Attributes["step"] = "still changing"
+				}
+				close(finished)
+				return nil, nil
+			}, "SlowIntent"),
+		},
+		ErrorHandlers: []askgo.ErrorHandler{attributeReporter{}},
+	}
+
+	// Without a Timeout the Lambda deadline applies, the error handlers get the request as sent
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	envelope := &askgo.RequestEnvelope{
+		Session: alexa.Session{Attributes: map[string]interface{}{"step": "original"}},
+		Request: alexa.Request{Type: "IntentRequest", Intent: alexa.Intent{Name: "SlowIntent"}},
+	}
+	result, err := skill.ProcessRequest(askgo.NewDefaultHandler(ctx, envelope))
+	require.NoError(t, err)
+	require.Equal(t, "<speak>original</speak>", result.(*askgo.ResponseEnvelope).Response.OutputSpeech.SSML)
+	<-finished
+}
+
+// responseCounter counts the responses given to the response interceptors
+type responseCounter struct {
+	calls chan struct{}
+}
+
+func (counter responseCounter) Process(input askgo.HandlerInput, response *askgo.ResponseEnvelope) error {
+	counter.calls <- struct{}{}
+	return nil
+}
+
+func Test_TimeoutDiscardsLateResults(t *testing.T) {
+	finished := make(chan struct{})
+	counter := responseCounter{calls: make(chan struct{}, 2)}
+	errorHandler := &recordingErrorHandler{}
+	skill := &askgo.Skill{
+		IgnoreTimestamp:      true,
+		Logger:               discardLogger,
+		Timeout:              50 * time.Millisecond,
+		ResponseInterceptors: []askgo.ResponseInterceptor{counter},
+		Handlers: []askgo.RequestHandler{
+			askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				<-input.GetContext().Done()
+				defer close(finished)
+				return input.GetResponse().Speak("Too late"), nil
+			}, "SlowIntent"),
+			askgo.IntentRoute(func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				<-input.GetContext().Done()
+				defer close(finished)
+				return nil, errors.New("too late to fail")
+			}, "FailingIntent"),
+		},
+		ErrorHandlers: []askgo.ErrorHandler{errorHandler},
+	}
+
+	for _, intent := range []string{"SlowIntent", "FailingIntent"} {
+		finished = make(chan struct{})
+		envelope := &askgo.RequestEnvelope{Request: alexa.Request{Type: "IntentRequest", Intent: alexa.Intent{Name: intent}}}
+		result, err := skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), envelope))
+		require.NoError(t, err)
+		require.Equal(t, "<speak>Sorry</speak>", result.(*askgo.ResponseEnvelope).Response.OutputSpeech.SSML)
+		<-finished
+
+		// The late result is neither intercepted nor given to the error handlers
+		require.Never(t, func() bool { return len(counter.calls) != 0 }, 50*time.Millisecond, time.Millisecond, intent)
+		require.Len(t, errorHandler.errors, 1, intent)
+		var timeoutErr *askgo.TimeoutError
+		require.True(t, errors.As(errorHandler.errors[0], &timeoutErr), intent)
+		errorHandler.errors = nil
+	}
+}
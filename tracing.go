@@ -0,0 +1,99 @@
+package askgo
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation name of the spans created by askgo
+const TracerName = "github.com/koblas/askgo"
+
+// Span names
+const (
+	SpanRequest             = "askgo.request"
+	SpanRequestInterceptor  = "askgo.request_interceptor"
+	SpanHandler             = "askgo.handler"
+	SpanResponseInterceptor = "askgo.response_interceptor"
+	SpanErrorHandler        = "askgo.error_handler"
+	SpanServiceCall         = "askgo.service_call"
+)
+
+// Span attribute keys
+const (
+	AttributeRequestID   = attribute.Key("alexa.request.id")
+	AttributeRequestType = attribute.Key("alexa.request.type")
+	AttributeIntent      = attribute.Key("alexa.intent.name")
+	AttributeLocale      = attribute.Key("alexa.locale")
+	AttributeSessionID   = attribute.Key("alexa.session.id")
+	AttributeHandler     = attribute.Key("askgo.handler")
+)
+
+// requestTracer creates the spans of a single request
+type requestTracer struct {
+	tracer trace.Tracer
+	root   trace.Span
+}
+
+// startRequestSpan starts the root span of the request and sets it on the context of the input
+func startRequestSpan(provider trace.TracerProvider, input HandlerInput, envelope RequestEnvelope) *requestTracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	tracer := provider.Tracer(TracerName)
+
+	request := envelope.Request
+	attributes := []attribute.KeyValue{
+		AttributeRequestID.String(request.RequestID),
+		AttributeRequestType.String(request.Type),
+		AttributeLocale.String(request.Locale),
+	}
+	if request.Intent.Name != "" {
+		attributes = append(attributes, AttributeIntent.String(request.Intent.Name))
+	}
+	if envelope.Session.SessionID != "" {
+		attributes = append(attributes, AttributeSessionID.String(envelope.Session.SessionID))
+	}
+
+	ctx, span := tracer.Start(input.GetContext(), SpanRequest,
+		trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attributes...))
+	input.SetContext(ctx)
+
+	return &requestTracer{tracer: tracer, root: span}
+}
+
+// start begins a child span of the request around a handler or interceptor, the span
+// is the current span of the input context until the returned function is called
+func (t *requestTracer) start(input HandlerInput, name string, handler interface{}) func(err error) {
+	ctx, span := t.tracer.Start(input.GetContext(), name, trace.WithAttributes(AttributeHandler.String(HandlerName(handler))))
+	input.SetContext(ctx)
+
+	return func(err error) {
+		recordError(span, err)
+		span.End()
+		// Keep whatever the handler added to the context, with the request as the current span
+		input.SetContext(trace.ContextWithSpan(input.GetContext(), t.root))
+	}
+}
+
+// end finishes the request span
+func (t *requestTracer) end(outcome string, err error) {
+	t.root.SetAttributes(attribute.String("askgo.outcome", outcome))
+	recordError(t.root, err)
+	t.root.End()
+}
+
+func recordError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// tracerFromContext returns a tracer from the provider of the current span
+func tracerFromContext(ctx context.Context) trace.Tracer {
+	return trace.SpanFromContext(ctx).TracerProvider().Tracer(TracerName)
+}
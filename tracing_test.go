@@ -0,0 +1,94 @@
+package askgo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type failingInterceptor struct{}
+
+func (failingInterceptor) Process(input askgo.HandlerInput, response *askgo.ResponseEnvelope) error {
+	return errors.New("invalid response")
+}
+
+type apologizer struct{}
+
+func (apologizer) CanHandle(input askgo.HandlerInput, err error) bool { return true }
+
+func (apologizer) Handle(input askgo.HandlerInput, err error) (*askgo.ResponseEnvelope, error) {
+	return input.GetResponse().Speak("Sorry"), nil
+}
+
+func Test_Tracing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.Write([]byte(`"Ada"`))
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	skill := &askgo.Skill{
+		IgnoreTimestamp: true,
+		TracerProvider:  provider,
+		Handlers: []askgo.RequestHandler{
+			&askgo.Route{Name: "greet", Intents: []string{"GreetIntent"}, Handler: func(input askgo.HandlerInput) (*askgo.ResponseEnvelope, error) {
+				_, span := trace.SpanFromContext(input.GetContext()).TracerProvider().Tracer("skill").Start(input.GetContext(), "lookup")
+				span.End()
+
+				var name string
+				if err := askgo.NewServiceClient(input).Do(input.GetContext(), "GET", "/v2/accounts/~current/settings/Profile.givenName", nil, &name); err != nil {
+					return nil, err
+				}
+				return input.GetResponse().Speak("Hello " + name), nil
+			}},
+		},
+		ResponseInterceptors: []askgo.ResponseInterceptor{failingInterceptor{}},
+		ErrorHandlers:        []askgo.ErrorHandler{apologizer{}},
+	}
+
+	envelope := &askgo.RequestEnvelope{
+		Request: alexa.Request{Type: "IntentRequest", RequestID: "request-1", Locale: "en-US", Intent: alexa.Intent{Name: "GreetIntent"}},
+		Context: alexa.Context{System: alexa.System{APIEndpoint: server.URL, APIAccessToken: "token"}},
+	}
+	_, err := skill.ProcessRequest(askgo.NewDefaultHandler(context.Background(), envelope))
+	require.NoError(t, err)
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	require.Len(t, spans, 6)
+
+	root := spans[askgo.SpanRequest]
+	require.False(t, root.Parent.IsValid())
+	require.Contains(t, root.Attributes, askgo.AttributeRequestID.String("request-1"))
+	require.Contains(t, root.Attributes, askgo.AttributeIntent.String("GreetIntent"))
+	require.Contains(t, root.Attributes, askgo.AttributeLocale.String("en-US"))
+	require.Contains(t, root.Attributes, attribute.String("askgo.outcome", askgo.OutcomeHandledError))
+
+	handler := spans[askgo.SpanHandler]
+	require.Equal(t, root.SpanContext.SpanID(), handler.Parent.SpanID())
+	require.Contains(t, handler.Attributes, askgo.AttributeHandler.String("greet"))
+	require.Equal(t, handler.SpanContext.SpanID(), spans["lookup"].Parent.SpanID())
+	require.Equal(t, handler.SpanContext.SpanID(), spans[askgo.SpanServiceCall].Parent.SpanID())
+	require.Contains(t, spans[askgo.SpanServiceCall].Attributes, attribute.Int("http.status_code", 200))
+
+	interceptor := spans[askgo.SpanResponseInterceptor]
+	require.Equal(t, root.SpanContext.SpanID(), interceptor.Parent.SpanID())
+	require.Equal(t, codes.Error, interceptor.Status.Code)
+	require.Equal(t, root.SpanContext.SpanID(), spans[askgo.SpanErrorHandler].Parent.SpanID())
+}
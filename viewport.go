@@ -0,0 +1,173 @@
+package askgo
+
+import "github.com/koblas/askgo/alexa"
+
+// ViewportProfile is the standard classification of a device screen
+type ViewportProfile string
+
+const (
+	// ViewportProfileHubRoundSmall is a small round hub (e.g. Echo Spot)
+	ViewportProfileHubRoundSmall ViewportProfile = "HUB-ROUND-SMALL"
+	// ViewportProfileHubLandscapeSmall is a small landscape hub (e.g. Echo Show 5)
+	ViewportProfileHubLandscapeSmall ViewportProfile = "HUB-LANDSCAPE-SMALL"
+	// ViewportProfileHubLandscapeMedium is a medium landscape hub (e.g. Echo Show 8)
+	ViewportProfileHubLandscapeMedium ViewportProfile = "HUB-LANDSCAPE-MEDIUM"
+	// ViewportProfileHubLandscapeLarge is a large landscape hub (e.g. Echo Show)
+	ViewportProfileHubLandscapeLarge ViewportProfile = "HUB-LANDSCAPE-LARGE"
+	// ViewportProfileMobileLandscapeSmall is a small mobile device held in landscape
+	ViewportProfileMobileLandscapeSmall ViewportProfile = "MOBILE-LANDSCAPE-SMALL"
+	// ViewportProfileMobilePortraitSmall is a small mobile device held in portrait
+	ViewportProfileMobilePortraitSmall ViewportProfile = "MOBILE-PORTRAIT-SMALL"
+	// ViewportProfileMobileLandscapeMedium is a tablet held in landscape
+	ViewportProfileMobileLandscapeMedium ViewportProfile = "MOBILE-LANDSCAPE-MEDIUM"
+	// ViewportProfileMobilePortraitMedium is a tablet held in portrait
+	ViewportProfileMobilePortraitMedium ViewportProfile = "MOBILE-PORTRAIT-MEDIUM"
+	// ViewportProfileTVLandscapeXLarge is a fullscreen TV (e.g. Fire TV)
+	ViewportProfileTVLandscapeXLarge ViewportProfile = "TV-LANDSCAPE-XLARGE"
+	// ViewportProfileTVPortraitMedium is the vertical overlay on a TV
+	ViewportProfileTVPortraitMedium ViewportProfile = "TV-PORTRAIT-MEDIUM"
+	// ViewportProfileTVLandscapeMedium is the horizontal overlay on a TV
+	ViewportProfileTVLandscapeMedium ViewportProfile = "TV-LANDSCAPE-MEDIUM"
+	// ViewportProfileUnknown is returned when there is no viewport or it doesn't match a profile
+	ViewportProfileUnknown ViewportProfile = "UNKNOWN-VIEWPORT-PROFILE"
+)
+
+// Size groups are ordered so they can be compared
+const (
+	viewportSizeXSmall = iota
+	viewportSizeSmall
+	viewportSizeMedium
+	viewportSizeLarge
+	viewportSizeXLarge
+)
+
+// DPI groups are ordered so they can be compared
+const (
+	viewportDpiXLow = iota
+	viewportDpiLow
+	viewportDpiMedium
+	viewportDpiHigh
+	viewportDpiXHigh
+	viewportDpiXXHigh
+)
+
+func viewportSizeGroup(size int) int {
+	switch {
+	case size < 600:
+		return viewportSizeXSmall
+	case size < 960:
+		return viewportSizeSmall
+	case size < 1280:
+		return viewportSizeMedium
+	case size < 1920:
+		return viewportSizeLarge
+	}
+	return viewportSizeXLarge
+}
+
+func viewportDpiGroup(dpi int) int {
+	switch {
+	case dpi < 121:
+		return viewportDpiXLow
+	case dpi < 161:
+		return viewportDpiLow
+	case dpi < 241:
+		return viewportDpiMedium
+	case dpi < 321:
+		return viewportDpiHigh
+	case dpi < 481:
+		return viewportDpiXHigh
+	}
+	return viewportDpiXXHigh
+}
+
+// GetViewportProfile classifies the viewport of the requesting device into one of the
+// standard profiles, the classification follows the one used by the Java and JavaScript SDKs.
+func GetViewportProfile(envelope RequestEnvelope) ViewportProfile {
+	return ClassifyViewport(envelope.Context.Viewport)
+}
+
+// ClassifyViewport classifies a viewport into one of the standard profiles
+func ClassifyViewport(viewport *alexa.Viewport) ViewportProfile {
+	if viewport == nil {
+		return ViewportProfileUnknown
+	}
+
+	width := viewportSizeGroup(viewport.CurrentPixelWidth)
+	height := viewportSizeGroup(viewport.CurrentPixelHeight)
+	dpi := viewportDpiGroup(viewport.DPI)
+
+	round := viewport.Shape == "ROUND"
+	rectangle := viewport.Shape == "RECTANGLE"
+	landscape := viewport.CurrentPixelWidth > viewport.CurrentPixelHeight
+	portrait := viewport.CurrentPixelWidth < viewport.CurrentPixelHeight
+	equal := viewport.CurrentPixelWidth == viewport.CurrentPixelHeight
+
+	switch {
+	case round && equal && dpi == viewportDpiLow && width == viewportSizeXSmall && height == viewportSizeXSmall:
+		return ViewportProfileHubRoundSmall
+	case rectangle && landscape && dpi == viewportDpiLow && width <= viewportSizeMedium && height <= viewportSizeXSmall:
+		return ViewportProfileHubLandscapeSmall
+	case rectangle && landscape && dpi == viewportDpiLow && width <= viewportSizeMedium && height <= viewportSizeSmall:
+		return ViewportProfileHubLandscapeMedium
+	case rectangle && landscape && dpi == viewportDpiLow && width >= viewportSizeLarge && height >= viewportSizeSmall:
+		return ViewportProfileHubLandscapeLarge
+	case rectangle && landscape && dpi == viewportDpiMedium && width >= viewportSizeMedium && height >= viewportSizeSmall:
+		return ViewportProfileMobileLandscapeMedium
+	case rectangle && portrait && dpi == viewportDpiMedium && width >= viewportSizeSmall && height >= viewportSizeMedium:
+		return ViewportProfileMobilePortraitMedium
+	case rectangle && landscape && dpi == viewportDpiMedium && width >= viewportSizeSmall && height >= viewportSizeXSmall:
+		return ViewportProfileMobileLandscapeSmall
+	case rectangle && portrait && dpi == viewportDpiMedium && width >= viewportSizeXSmall && height >= viewportSizeSmall:
+		return ViewportProfileMobilePortraitSmall
+	case rectangle && landscape && dpi >= viewportDpiHigh && width >= viewportSizeXLarge && height >= viewportSizeMedium:
+		return ViewportProfileTVLandscapeXLarge
+	case rectangle && portrait && dpi >= viewportDpiHigh && width == viewportSizeXSmall && height == viewportSizeXLarge:
+		return ViewportProfileTVPortraitMedium
+	case rectangle && landscape && dpi >= viewportDpiHigh && width == viewportSizeMedium && height == viewportSizeSmall:
+		return ViewportProfileTVLandscapeMedium
+	}
+
+	return ViewportProfileUnknown
+}
+
+// ViewportVariant updates a response for a specific viewport profile (e.g. adds a display template)
+type ViewportVariant func(response *ResponseEnvelope)
+
+// ViewportVariants holds the per-profile variants of a response, allowing a handler to
+// render a different template or image size for every class of device.
+type ViewportVariants struct {
+	// Variants registered by profile
+	Variants map[ViewportProfile]ViewportVariant
+	// Default is applied when there is no variant for the profile of the device,
+	// it is not applied to devices without a screen.
+	Default ViewportVariant
+}
+
+// Register adds the variant used for the given profiles
+func (variants *ViewportVariants) Register(variant ViewportVariant, profiles ...ViewportProfile) *ViewportVariants {
+	if variants.Variants == nil {
+		variants.Variants = make(map[ViewportProfile]ViewportVariant)
+	}
+	for _, profile := range profiles {
+		variants.Variants[profile] = variant
+	}
+
+	return variants
+}
+
+// Apply updates the response with the variant matching the viewport of the requesting device
+func (variants *ViewportVariants) Apply(input HandlerInput, response *ResponseEnvelope) *ResponseEnvelope {
+	viewport := input.GetRequestEnvelope().Context.Viewport
+	if viewport == nil {
+		return response
+	}
+
+	if variant, found := variants.Variants[ClassifyViewport(viewport)]; found {
+		variant(response)
+	} else if variants.Default != nil {
+		variants.Default(response)
+	}
+
+	return response
+}
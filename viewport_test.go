@@ -0,0 +1,57 @@
+package askgo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/koblas/askgo"
+	"github.com/koblas/askgo/alexa"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ClassifyViewport(t *testing.T) {
+	cases := []struct {
+		viewport *alexa.Viewport
+		profile  askgo.ViewportProfile
+	}{
+		{nil, askgo.ViewportProfileUnknown},
+		{&alexa.Viewport{Shape: "ROUND", CurrentPixelWidth: 480, CurrentPixelHeight: 480, DPI: 160}, askgo.ViewportProfileHubRoundSmall},
+		{&alexa.Viewport{Shape: "RECTANGLE", CurrentPixelWidth: 960, CurrentPixelHeight: 480, DPI: 160}, askgo.ViewportProfileHubLandscapeSmall},
+		{&alexa.Viewport{Shape: "RECTANGLE", CurrentPixelWidth: 1024, CurrentPixelHeight: 600, DPI: 160}, askgo.ViewportProfileHubLandscapeMedium},
+		{&alexa.Viewport{Shape: "RECTANGLE", CurrentPixelWidth: 1280, CurrentPixelHeight: 800, DPI: 160}, askgo.ViewportProfileHubLandscapeLarge},
+		{&alexa.Viewport{Shape: "RECTANGLE", CurrentPixelWidth: 1920, CurrentPixelHeight: 1080, DPI: 320}, askgo.ViewportProfileTVLandscapeXLarge},
+		{&alexa.Viewport{Shape: "RECTANGLE", CurrentPixelWidth: 600, CurrentPixelHeight: 1024, DPI: 240}, askgo.ViewportProfileMobilePortraitMedium},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.profile, askgo.ClassifyViewport(c.viewport))
+	}
+}
+
+func Test_ViewportVariants(t *testing.T) {
+	variants := &askgo.ViewportVariants{
+		Default: func(response *askgo.ResponseEnvelope) {
+			response.WithSimpleCard("default", "")
+		},
+	}
+	variants.Register(func(response *askgo.ResponseEnvelope) {
+		response.WithSimpleCard("round", "")
+	}, askgo.ViewportProfileHubRoundSmall)
+
+	envelope := &askgo.RequestEnvelope{}
+	envelope.Context.Viewport = &alexa.Viewport{Shape: "ROUND", CurrentPixelWidth: 480, CurrentPixelHeight: 480, DPI: 160}
+	input := askgo.NewDefaultHandler(context.Background(), envelope)
+	response := variants.Apply(input, input.GetResponse())
+	require.Equal(t, "round", response.Response.Card.Title)
+
+	envelope.Context.Viewport.Shape = "RECTANGLE"
+	envelope.Context.Viewport.CurrentPixelWidth = 1024
+	input = askgo.NewDefaultHandler(context.Background(), envelope)
+	response = variants.Apply(input, input.GetResponse())
+	require.Equal(t, "default", response.Response.Card.Title)
+
+	envelope.Context.Viewport = nil
+	input = askgo.NewDefaultHandler(context.Background(), envelope)
+	response = variants.Apply(input, input.GetResponse())
+	require.Nil(t, response.Response)
+}